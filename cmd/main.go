@@ -3,30 +3,150 @@ package main
 import (
 	router "IFJudger/internal"
 	"IFJudger/pkg/config"
+	"IFJudger/pkg/logger"
+	"IFJudger/pkg/tracing"
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
+	"google.golang.org/grpc"
 	_ "modernc.org/sqlite"
 )
 
+// cada etapa do desligamento depois da drenagem dos workers tem o próprio prazo, para que uma
+// etapa lenta não tire o tempo das seguintes
+const (
+	// requisições HTTP em andamento, inclusive streams SSE
+	httpShutdownTimeout = 5 * time.Second
+	// chamadas gRPC em andamento, inclusive streams de WatchSubmission
+	grpcShutdownTimeout = 5 * time.Second
+	// exportação dos spans pendentes
+	tracingShutdownTimeout = 5 * time.Second
+)
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	envConfigs, err := config.LoadConfig()
 	if err != nil {
 		panic(err.Error())
 	}
 
+	if err := logger.Setup(os.Stderr, envConfigs.LogFormat, envConfigs.LogLevel); err != nil {
+		panic(err.Error())
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), envConfigs.TracesExporter, envConfigs.ServiceName)
+	if err != nil {
+		fatal("Falha ao configurar o tracing", err)
+	}
+
+	if envConfigs.AuthEnabled && envConfigs.BootstrapAPIKey == "" {
+		slog.Warn("AUTH_ENABLED=true sem BOOTSTRAP_API_KEY: só as chaves já cadastradas terão acesso.")
+	}
+
 	db, err := sql.Open("sqlite", envConfigs.DatabasePath)
 	if err != nil {
-		log.Fatal(err)
+		fatal("Falha ao abrir o banco", err)
 	}
 	defer db.Close()
 
 	if err := db.Ping(); err != nil {
-		log.Fatal(err)
+		fatal("Falha ao conectar no banco", err)
 	}
-	log.Println("Database connection success")
+	slog.Info("Database connection success", "path", envConfigs.DatabasePath)
+
+	mux, grpcServer, shutdownWorkers := router.StartRoutes(envConfigs, db)
+	server := &http.Server{Addr: ":8080", Handler: mux}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("Servidor HTTP ouvindo", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("Falha no servidor HTTP", err)
+		}
+	}()
+
+	if envConfigs.GRPCPort > 0 {
+		listener, err := net.Listen("tcp", ":"+strconv.Itoa(envConfigs.GRPCPort))
+		if err != nil {
+			fatal("Falha ao abrir a porta do servidor gRPC", err)
+		}
+		go func() {
+			slog.Info("Servidor gRPC ouvindo", "addr", listener.Addr().String())
+			if err := grpcServer.Serve(listener); err != nil {
+				fatal("Falha no servidor gRPC", err)
+			}
+		}()
+	} else {
+		slog.Info("GRPC_PORT=0: servidor gRPC desligado")
+	}
+
+	<-ctx.Done()
+	stop()
+	slog.Info("Sinal de desligamento recebido, drenando workers", "timeout", envConfigs.ShutdownTimeout.String())
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), envConfigs.ShutdownTimeout)
+	defer cancelDrain()
+	if err := shutdownWorkers(drainCtx); err != nil {
+		slog.Warn("Drenagem incompleta, jobs restantes voltaram para a fila", logger.Err(err))
+	}
+
+	httpCtx, cancelHTTP := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancelHTTP()
+	if err := server.Shutdown(httpCtx); errors.Is(err, context.DeadlineExceeded) {
+		// streams SSE de /job/{id}/events seguram a conexão até o job terminar: passado o prazo, são cortados
+		slog.Warn("Conexões HTTP ainda abertas após o prazo, encerrando à força")
+		if err := server.Close(); err != nil {
+			slog.Error("Falha ao encerrar servidor HTTP", logger.Err(err))
+		}
+	} else if err != nil {
+		slog.Error("Falha ao encerrar servidor HTTP", logger.Err(err))
+	}
+
+	grpcCtx, cancelGRPC := context.WithTimeout(context.Background(), grpcShutdownTimeout)
+	defer cancelGRPC()
+	stopGRPC(grpcCtx, grpcServer)
+
+	// por último, para exportar também os spans dos jobs drenados e das últimas requisições
+	tracingCtx, cancelTracing := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancelTracing()
+	if err := shutdownTracing(tracingCtx); err != nil {
+		slog.Error("Falha ao exportar os spans pendentes", logger.Err(err))
+	}
+
+	slog.Info("Servidor encerrado.")
+}
+
+// stopGRPC espera as chamadas gRPC em andamento até ctx expirar e então encerra as que restarem,
+// como os streams de WatchSubmission que ainda acompanham submissões.
+func stopGRPC(ctx context.Context, grpcServer *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+}
 
-	mux := router.StartRoutes(envConfigs, db)
-	http.ListenAndServe(":8080", mux)
+func fatal(msg string, err error) {
+	slog.Error(msg, logger.Err(err))
+	os.Exit(1)
 }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/internal/services"
+	"IFJudger/pkg/config"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// runValidate implementa o subcomando validate: confere pacotes de problema (diretórios extraídos
+// ou arquivos .zip) com a mesma validação do download e do upload, sem subir o servidor.
+// Devolve 1 se algum pacote tiver erros e 2 para uso incorreto.
+func runValidate(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "uso: judger validate [-max-file-mb N] [-max-extracted-mb N] <pacote.zip|diretório>...")
+		flags.PrintDefaults()
+	}
+
+	// os padrões vêm de PROBLEM_FILE_MAX_MB e PROBLEM_EXTRACTED_MAX_MB, para a validação ser a mesma do servidor
+	maxFileMB, maxExtractedMB := 0, 0
+	if cfg, err := config.LoadConfig(); err == nil {
+		maxFileMB, maxExtractedMB = cfg.ProblemFileMaxMB, cfg.ProblemExtractedMaxMB
+	}
+	flags.IntVar(&maxFileMB, "max-file-mb", maxFileMB, "tamanho máximo de cada arquivo do pacote, em MB")
+	flags.IntVar(&maxExtractedMB, "max-extracted-mb", maxExtractedMB, "soma máxima dos arquivos extraídos do .zip, em MB")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 || maxFileMB <= 0 || maxExtractedMB <= 0 {
+		flags.Usage()
+		return 2
+	}
+
+	exitCode := 0
+	for _, path := range flags.Args() {
+		report, err := validatePath(path, int64(maxFileMB)<<20, int64(maxExtractedMB)<<20)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+			exitCode = 1
+			continue
+		}
+
+		for _, issue := range report.Issues {
+			location := path
+			if issue.File != "" {
+				location += ": " + issue.File
+			}
+			fmt.Fprintf(stdout, "%s: %s [%s] %s\n", location, issue.Severity, issue.Code, issue.Message)
+		}
+		if len(report.Errors()) > 0 {
+			exitCode = 1
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: ok (%d casos de teste, %d linguagens, %d avisos)\n", path, report.TestCases, len(report.Languages), len(report.Warnings()))
+	}
+	return exitCode
+}
+
+// validatePath valida um diretório como está ou extrai o .zip antes. Os limites de tamanho valem
+// para os dois: no .zip são conferidos durante a extração, no diretório antes da validação.
+func validatePath(path string, maxFileBytes int64, maxExtractedBytes int64) (models.PackageReport, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return models.PackageReport{}, err
+	}
+
+	dir := path
+	if info.IsDir() {
+		err = services.CheckPackageSize(dir, maxFileBytes, maxExtractedBytes)
+	} else {
+		if !strings.HasSuffix(strings.ToLower(path), ".zip") {
+			return models.PackageReport{}, fmt.Errorf("esperado um diretório ou um arquivo .zip")
+		}
+
+		dir, err = os.MkdirTemp("", "validate-*")
+		if err != nil {
+			return models.PackageReport{}, err
+		}
+		defer os.RemoveAll(dir)
+
+		// um arquivo grande demais interrompe a extração; ele é o único problema relatado
+		err = services.ExtractPackage(path, dir, maxFileBytes, maxExtractedBytes)
+	}
+
+	var packageErr *customErrors.PackageError
+	switch {
+	case errors.As(err, &packageErr):
+		return models.PackageReport{Issues: packageErr.Issues}, nil
+	case errors.Is(err, customErrors.ErrInvalidPackage):
+		return models.PackageReport{}, fmt.Errorf("zip inválido: %w", err)
+	case err != nil:
+		return models.PackageReport{}, err
+	}
+	return services.ValidatePackage(dir)
+}
@@ -0,0 +1,161 @@
+// Package apierror escreve as respostas de erro da API no envelope JSON comum,
+// {"error": {"code": "...", "message": "...", "details": {...}}}, e traduz os erros dos serviços.
+package apierror
+
+import (
+	"IFJudger/internal/api/dto"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/pkg/logger"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Códigos de erro devolvidos no campo error.code.
+const (
+	CodeInvalidJSON      = "invalid_json"
+	CodeInvalidParameter = "invalid_parameter"
+	CodeInvalidCursor    = "invalid_cursor"
+	CodePayloadTooLarge  = "payload_too_large"
+	CodeValidation       = "validation_failed"
+	CodeInvalidLanguage  = "invalid_language"
+	CodeInvalidWebhook   = "invalid_webhook_url"
+	CodeInvalidFilter    = "invalid_filter"
+	CodeIdempotencyReuse = "idempotency_key_reused"
+	CodeInvalidPackage   = "invalid_package"
+	CodeNotFound         = "not_found"
+	CodeProblemNotFound  = "problem_not_found"
+	CodeRouteNotFound    = "route_not_found"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeRateLimited      = "rate_limited"
+	CodeQuotaExceeded    = "quota_exceeded"
+	CodeQueueFull        = "queue_full"
+	CodeShuttingDown     = "shutting_down"
+	CodeSuperseded       = "callback_superseded"
+	CodeInternal         = "internal_error"
+)
+
+// espera sugerida no Retry-After quando a fila está cheia ou o serviço está desligando
+const unavailableRetryAfter = 10 * time.Second
+
+// Write responde com o envelope de erro. details é opcional.
+func Write(w http.ResponseWriter, status int, code, message string, details map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(dto.ErrorResponseDTO{
+		Error: dto.ErrorDTO{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+	})
+}
+
+// WriteError traduz um erro dos serviços para o status e o código correspondentes.
+// Erros sem sentinela conhecida viram 500, com a mensagem original apenas no log.
+func WriteError(w http.ResponseWriter, err error) {
+	var batchErr *customErrors.BatchError
+	if errors.As(err, &batchErr) {
+		writeBatchError(w, batchErr)
+		return
+	}
+
+	var packageErr *customErrors.PackageError
+	if errors.As(err, &packageErr) {
+		writePackageError(w, packageErr)
+		return
+	}
+
+	switch {
+	case errors.Is(err, customErrors.ErrValidation):
+		Write(w, http.StatusUnprocessableEntity, CodeValidation, err.Error(), nil)
+	case errors.Is(err, customErrors.ErrInvalidLanguage):
+		Write(w, http.StatusUnprocessableEntity, CodeInvalidLanguage, err.Error(), nil)
+	case errors.Is(err, customErrors.ErrInvalidWebhookURL):
+		Write(w, http.StatusUnprocessableEntity, CodeInvalidWebhook, err.Error(), nil)
+	case errors.Is(err, customErrors.ErrInvalidFilter):
+		Write(w, http.StatusUnprocessableEntity, CodeInvalidFilter, err.Error(), nil)
+	case errors.Is(err, customErrors.ErrIdempotencyConflict):
+		Write(w, http.StatusUnprocessableEntity, CodeIdempotencyReuse, err.Error(), nil)
+	case errors.Is(err, customErrors.ErrInvalidPackage):
+		Write(w, http.StatusUnprocessableEntity, CodeInvalidPackage, err.Error(), nil)
+	case errors.Is(err, customErrors.ErrPackageTooLarge):
+		Write(w, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, err.Error(), nil)
+	case errors.Is(err, customErrors.ErrInvalidCursor):
+		Write(w, http.StatusBadRequest, CodeInvalidCursor, err.Error(), map[string]any{"parameter": "cursor"})
+	case errors.Is(err, customErrors.ErrProblemNotFound):
+		Write(w, http.StatusNotFound, CodeProblemNotFound, err.Error(), nil)
+	case errors.Is(err, customErrors.ErrNotFound):
+		Write(w, http.StatusNotFound, CodeNotFound, "Resource not found", nil)
+	case errors.Is(err, customErrors.ErrUnauthorized):
+		Write(w, http.StatusUnauthorized, CodeUnauthorized, "Missing or invalid API key", nil)
+	case errors.Is(err, customErrors.ErrCallbackSuperseded):
+		Write(w, http.StatusConflict, CodeSuperseded, err.Error(), nil)
+	case errors.Is(err, customErrors.ErrQuotaExceeded):
+		Write(w, http.StatusTooManyRequests, CodeQuotaExceeded, err.Error(), nil)
+	case errors.Is(err, customErrors.ErrQueueFull):
+		writeUnavailable(w, CodeQueueFull, err.Error())
+	case errors.Is(err, customErrors.ErrShuttingDown):
+		writeUnavailable(w, CodeShuttingDown, err.Error())
+	default:
+		logger.Component("api").Error("Erro interno ao atender requisição", logger.Err(err))
+		Write(w, http.StatusInternalServerError, CodeInternal, "Internal server error", nil)
+	}
+}
+
+// writeBatchError responde 422 com o erro de cada submissão inválida do lote em details.items.
+func writeBatchError(w http.ResponseWriter, batchErr *customErrors.BatchError) {
+	items := make([]map[string]any, len(batchErr.Items))
+	for i, item := range batchErr.Items {
+		items[i] = map[string]any{
+			"index":   item.Index,
+			"code":    itemCode(item.Err),
+			"message": item.Err.Error(),
+		}
+	}
+	Write(w, http.StatusUnprocessableEntity, CodeValidation, batchErr.Error(), map[string]any{"items": items})
+}
+
+// writePackageError responde 422 com os erros da validação do pacote em details.issues.
+func writePackageError(w http.ResponseWriter, packageErr *customErrors.PackageError) {
+	issues := make([]map[string]any, len(packageErr.Issues))
+	for i, issue := range packageErr.Issues {
+		issues[i] = map[string]any{
+			"code":    issue.Code,
+			"message": issue.Message,
+		}
+		if issue.File != "" {
+			issues[i]["file"] = issue.File
+		}
+	}
+	Write(w, http.StatusUnprocessableEntity, CodeInvalidPackage, packageErr.Error(), map[string]any{"issues": issues})
+}
+
+// itemCode é o código que a submissão teria recebido se fosse enviada sozinha ao /submit.
+func itemCode(err error) string {
+	switch {
+	case errors.Is(err, customErrors.ErrInvalidLanguage):
+		return CodeInvalidLanguage
+	case errors.Is(err, customErrors.ErrInvalidWebhookURL):
+		return CodeInvalidWebhook
+	case errors.Is(err, customErrors.ErrProblemNotFound):
+		return CodeProblemNotFound
+	default:
+		return CodeValidation
+	}
+}
+
+func writeUnavailable(w http.ResponseWriter, code, message string) {
+	seconds := int(unavailableRetryAfter.Seconds())
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	Write(w, http.StatusServiceUnavailable, code, message, map[string]any{"retry_after_seconds": seconds})
+}
+
+// HandleRouteNotFound responde às rotas inexistentes dentro de /v1 com o mesmo envelope.
+func HandleRouteNotFound(w http.ResponseWriter, r *http.Request) {
+	Write(w, http.StatusNotFound, CodeRouteNotFound, "No route for "+r.Method+" "+r.URL.Path, nil)
+}
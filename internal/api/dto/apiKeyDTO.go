@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyRequestDTO struct {
+	Name               string   `json:"name"`
+	Scopes             []string `json:"scopes"` // "submit", "read", "admin"
+	RateLimitPerMinute int      `json:"rate_limit_per_minute"`
+	MaxActiveJobs      int      `json:"max_active_jobs"`
+}
+
+type APIKeyDTO struct {
+	ID                 string     `json:"id"`
+	Name               string     `json:"name"`
+	Scopes             []string   `json:"scopes"`
+	RateLimitPerMinute int        `json:"rate_limit_per_minute"`
+	MaxActiveJobs      int        `json:"max_active_jobs"`
+	CreatedAt          time.Time  `json:"created_at"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKeyResponseDTO é a única resposta que contém a chave em texto; ela não pode ser recuperada depois.
+type CreateAPIKeyResponseDTO struct {
+	APIKeyDTO
+	Key string `json:"key"`
+}
+
+type APIKeyListResponseDTO struct {
+	Keys []APIKeyDTO `json:"keys"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+type CallbackDeliveryDTO struct {
+	ID            int64     `json:"id"`
+	SubmissionID  string    `json:"submission_id"`
+	URL           string    `json:"url"`
+	Status        string    `json:"status"` // "pending", "delivered", "failed"
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type CallbackListResponseDTO struct {
+	Callbacks []CallbackDeliveryDTO `json:"callbacks"`
+}
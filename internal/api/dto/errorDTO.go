@@ -0,0 +1,12 @@
+package dto
+
+// ErrorResponseDTO é o envelope de todas as respostas de erro da API.
+type ErrorResponseDTO struct {
+	Error ErrorDTO `json:"error"`
+}
+
+type ErrorDTO struct {
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+type ComponentHealthDTO struct {
+	Status    string `json:"status"` // "ok", "fail"
+	Message   string `json:"message,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+type HealthResponseDTO struct {
+	Status     string                        `json:"status"` // "ok", "fail"
+	Components map[string]ComponentHealthDTO `json:"components,omitempty"`
+}
@@ -4,4 +4,25 @@ type JudgeRequest struct {
 	ProblemID     string `json:"problem_id"`
 	LanguageToken string `json:"language_token"`
 	Code          string `json:"code"`
+	Priority      string `json:"priority"`
+	WebhookURL    string `json:"webhook_url"`
+	ClientID      string `json:"client_id"`
+	ClientQuota   int    `json:"client_quota"`
+	// IdempotencyKey vem do header Idempotency-Key do POST /submit
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+type RunRequest struct {
+	LanguageToken string `json:"language_token"`
+	Code          string `json:"code"`
+	Input         string `json:"input"`
+	WebhookURL    string `json:"webhook_url"`
+	ClientID      string `json:"client_id"`
+	ClientQuota   int    `json:"client_quota"`
+}
+
+type BatchJudgeRequest struct {
+	Submissions []JudgeRequest `json:"submissions"`
+	ClientID    string         `json:"client_id"`
+	ClientQuota int            `json:"client_quota"`
 }
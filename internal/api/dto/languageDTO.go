@@ -0,0 +1,27 @@
+package dto
+
+type LanguageDTO struct {
+	Token          string `json:"token"`
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	Image          string `json:"image"`
+	FileExtension  string `json:"file_extension"`
+	CompileCommand string `json:"compile_command,omitempty"` // ausente nas linguagens interpretadas
+	RunCommand     string `json:"run_command"`
+}
+
+type LanguageListResponseDTO struct {
+	Languages []LanguageDTO `json:"languages"`
+}
+
+// ProblemLanguageDTO é uma linguagem aceita pelo problema, com os limites do meta.json dele.
+type ProblemLanguageDTO struct {
+	LanguageDTO
+	TimeLimitSeconds int `json:"time_limit_seconds"`
+	MemoryLimitMB    int `json:"memory_limit_mb"`
+}
+
+type ProblemLanguagesResponseDTO struct {
+	ProblemID string               `json:"problem_id"`
+	Languages []ProblemLanguageDTO `json:"languages"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type ProblemPackageLanguageDTO struct {
+	Language         string `json:"language"`
+	TimeLimitSeconds int    `json:"time_limit_seconds"`
+	MemoryLimitMB    int    `json:"memory_limit_mb"`
+}
+
+type ProblemUploadResponseDTO struct {
+	ProblemID   string                      `json:"problem_id"`
+	Version     string                      `json:"version"`
+	SHA256      string                      `json:"sha256"`
+	TestCases   int                         `json:"test_cases"`
+	Languages   []ProblemPackageLanguageDTO `json:"languages"`
+	InstalledAt time.Time                   `json:"installed_at"`
+	Replaced    bool                        `json:"replaced"` // já havia uma versão do problema no cache
+	Warnings    []PackageIssueDTO           `json:"warnings,omitempty"`
+}
+
+// PackageIssueDTO é um aviso da validação do pacote, que não impediu a instalação.
+type PackageIssueDTO struct {
+	Code    string `json:"code"`
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
@@ -1,9 +1,13 @@
 package dto
 
+import "time"
+
 type SubmissionRequestDTO struct {
-	ProblemID string `json:"problem_id"`
-	Language  string `json:"language"`
-	Code      string `json:"code"`
+	ProblemID  string `json:"problem_id"`
+	Language   string `json:"language"`
+	Code       string `json:"code"`
+	Priority   string `json:"priority,omitempty"` // contest, practice (padrão) ou rejudge
+	WebhookURL string `json:"webhook_url,omitempty"`
 }
 
 type SubmissionResponseDTO struct {
@@ -11,9 +15,52 @@ type SubmissionResponseDTO struct {
 	Message string `json:"message"`
 }
 
+type SubmissionSummaryDTO struct {
+	ID           string     `json:"id"`
+	ProblemID    string     `json:"problem_id,omitempty"`
+	Language     string     `json:"language,omitempty"`
+	Priority     string     `json:"priority"`
+	Status       string     `json:"status"`
+	ErrorMessage string     `json:"error,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"` // ausente em submissões anteriores à listagem
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type SubmissionListResponseDTO struct {
+	Submissions []SubmissionSummaryDTO `json:"submissions"`
+	NextCursor  string                 `json:"next_cursor,omitempty"`
+}
+
 type StatusResponseDTO struct {
 	ID           string      `json:"id"`
 	Status       string      `json:"status"`
 	Result       interface{} `json:"result,omitempty"`
 	ErrorMessage string      `json:"error,omitempty"`
 }
+
+type BatchRequestDTO struct {
+	Submissions []SubmissionRequestDTO `json:"submissions"`
+}
+
+type BatchResponseDTO struct {
+	BatchID string   `json:"batch_id"`
+	Tokens  []string `json:"tokens"` // na mesma ordem de submissions
+	Message string   `json:"message"`
+}
+
+type BatchItemDTO struct {
+	Index        int    `json:"index"`
+	Token        string `json:"token"`
+	ProblemID    string `json:"problem_id"`
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error,omitempty"`
+}
+
+type BatchStatusResponseDTO struct {
+	ID        string         `json:"id"`
+	Total     int            `json:"total"`
+	Counts    map[string]int `json:"counts"` // submissões por status
+	Done      bool           `json:"done"`
+	CreatedAt time.Time      `json:"created_at"`
+	Items     []BatchItemDTO `json:"items"`
+}
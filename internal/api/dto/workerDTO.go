@@ -1,17 +1,23 @@
 package dto
 
 type ExecutionRequest struct {
+	Language   string `json:"language"`
 	Code       string `json:"code"`
 	Input      string `json:"input"`
 	WebhookURL string `json:"webhook_url"`
 }
 
 type ExecutionResponse struct {
-	ID     string `json:"id"`
-	Status string `json:"status"` // "queued", "processing", "success", "error"
-	Stdout string `json:"stdout"`
-	Stderr string `json:"stderr"`
-	Error  string `json:"error"`
+	ID        string `json:"id"`
+	Status    string `json:"status"`               // "queued", "processing", "success", "error"
+	RunStatus string `json:"run_status,omitempty"` // "OK", "TLE", "RTE"
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	Truncated bool   `json:"truncated,omitempty"`
+	ExitCode  int    `json:"exit_code"`
+	TimeMS    int64  `json:"time_ms"`
+	MemoryKB  int64  `json:"memory_kb"`
+	Error     string `json:"error"`
 }
 
 type ExecutionEnqueuedResponse struct {
@@ -0,0 +1,20 @@
+// Package openapi embute a especificação OpenAPI da API (openapi.json), servida em GET /v1/openapi.json.
+//
+// A especificação é a referência do cliente em pkg/client. O openapi_test.go sobe as rotas reais e
+// confere cada resposta, inclusive as decodificadas pelo cliente, com o documento.
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed openapi.json
+var document []byte
+
+// Handle serve o documento como está no repositório.
+func Handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
@@ -0,0 +1,550 @@
+package openapi_test
+
+import (
+	router "IFJudger/internal"
+	"IFJudger/pkg/client"
+	"IFJudger/pkg/config"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	_ "modernc.org/sqlite"
+)
+
+const bootstrapKey = "chave-de-teste-admin"
+
+// TestResponsesMatchSpec sobe as rotas reais e passa todas as respostas pela especificação: status
+// documentado e corpo válido pelo schema. As chamadas feitas pelo pkg/client também conferem que o
+// cliente decodifica o corpo inteiro, sem campos que ele desconheça.
+func TestResponsesMatchSpec(t *testing.T) {
+	server := startJudger(t)
+	doc := loadSpec(t, server.URL)
+
+	transport := newSpecTransport(t, doc)
+	httpClient := &http.Client{Transport: transport}
+	admin := client.New(server.URL, client.WithAPIKey(bootstrapKey), client.WithHTTPClient(httpClient))
+	anonymous := client.New(server.URL, client.WithHTTPClient(httpClient))
+	ctx := context.Background()
+
+	t.Run("operacionais", func(t *testing.T) {
+		for _, path := range []string{"/healthz", "/readyz", "/metrics"} {
+			resp, err := httpClient.Get(server.URL + path)
+			if err != nil {
+				t.Fatalf("GET %s: %v", path, err)
+			}
+			resp.Body.Close()
+		}
+
+		resp, err := httpClient.Get(server.URL + "/v1/openapi.json")
+		if err != nil {
+			t.Fatalf("GET /v1/openapi.json: %v", err)
+		}
+		defer resp.Body.Close()
+		served, _ := io.ReadAll(resp.Body)
+		embedded, _ := os.ReadFile("openapi.json")
+		if !bytes.Equal(served, embedded) {
+			t.Error("GET /v1/openapi.json não serve o openapi.json do repositório")
+		}
+	})
+
+	t.Run("problemas", func(t *testing.T) {
+		upload, err := admin.UploadProblem(ctx, "soma", bytes.NewReader(problemPackage(t, "1 2\n")))
+		if err != nil {
+			t.Fatalf("UploadProblem: %v", err)
+		}
+		checkDecoded(t, transport, upload)
+		if upload.Replaced || upload.TestCases != 1 {
+			t.Errorf("primeiro upload: replaced=%v test_cases=%d", upload.Replaced, upload.TestCases)
+		}
+
+		upload, err = admin.UploadProblem(ctx, "soma", bytes.NewReader(problemPackage(t, "2 3\n")))
+		if err != nil {
+			t.Fatalf("UploadProblem (substituição): %v", err)
+		}
+		checkDecoded(t, transport, upload)
+		if !upload.Replaced {
+			t.Error("segundo upload deveria substituir a versão anterior")
+		}
+
+		_, err = admin.UploadProblem(ctx, "soma", strings.NewReader("não é um zip"))
+		expectAPIError(t, err, http.StatusUnprocessableEntity)
+
+		languages, err := admin.ListProblemLanguages(ctx, "soma")
+		if err != nil {
+			t.Fatalf("ListProblemLanguages: %v", err)
+		}
+		checkDecoded(t, transport, struct {
+			ProblemID string                   `json:"problem_id"`
+			Languages []client.ProblemLanguage `json:"languages"`
+		}{"soma", languages})
+
+		_, err = admin.ListProblemLanguages(ctx, "inexistente")
+		expectAPIError(t, err, http.StatusNotFound)
+	})
+
+	t.Run("linguagens", func(t *testing.T) {
+		languages, err := admin.ListLanguages(ctx)
+		if err != nil {
+			t.Fatalf("ListLanguages: %v", err)
+		}
+		checkDecoded(t, transport, struct {
+			Languages []client.Language `json:"languages"`
+		}{languages})
+
+		_, err = anonymous.ListLanguages(ctx)
+		expectAPIError(t, err, http.StatusUnauthorized)
+	})
+
+	var submitter *client.Client
+	var submitterKeyID string
+	t.Run("chaves", func(t *testing.T) {
+		created, err := admin.CreateAPIKey(ctx, client.CreateAPIKeyRequest{
+			Name:               "corretor",
+			Scopes:             []string{"submit", "read"},
+			RateLimitPerMinute: 1000,
+			MaxActiveJobs:      100,
+		})
+		if err != nil {
+			t.Fatalf("CreateAPIKey: %v", err)
+		}
+		checkDecoded(t, transport, created)
+		submitter = client.New(server.URL, client.WithAPIKey(created.Key), client.WithHTTPClient(httpClient))
+		submitterKeyID = created.ID
+
+		_, err = admin.CreateAPIKey(ctx, client.CreateAPIKeyRequest{Name: "sem escopo"})
+		expectAPIError(t, err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+
+		keys, err := admin.ListAPIKeys(ctx)
+		if err != nil {
+			t.Fatalf("ListAPIKeys: %v", err)
+		}
+		checkDecoded(t, transport, struct {
+			Keys []client.APIKey `json:"keys"`
+		}{keys})
+	})
+	if submitter == nil {
+		t.FailNow()
+	}
+
+	t.Run("submissões", func(t *testing.T) {
+		enqueued, err := submitter.Submit(ctx, client.SubmissionRequest{ProblemID: "soma", Language: "python", Code: "print(3)"})
+		if err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+		checkDecoded(t, transport, enqueued)
+
+		_, err = submitter.Submit(ctx, client.SubmissionRequest{ProblemID: "soma", Language: "cobol", Code: "x"})
+		expectAPIError(t, err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+
+		_, err = submitter.Submit(ctx, client.SubmissionRequest{ProblemID: "inexistente", Language: "python", Code: "x"})
+		expectAPIError(t, err, http.StatusNotFound)
+
+		submission, err := submitter.GetSubmission(ctx, enqueued.Token)
+		if err != nil {
+			t.Fatalf("GetSubmission: %v", err)
+		}
+		checkDecoded(t, transport, submission)
+
+		_, err = submitter.GetSubmission(ctx, "inexistente")
+		expectAPIError(t, err, http.StatusNotFound)
+
+		page, err := submitter.ListSubmissions(ctx, client.ListSubmissionsParams{ProblemID: "soma", Limit: 10})
+		if err != nil {
+			t.Fatalf("ListSubmissions: %v", err)
+		}
+		checkDecoded(t, transport, page)
+
+		_, err = submitter.ListSubmissions(ctx, client.ListSubmissionsParams{Status: "desconhecido"})
+		expectAPIError(t, err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+
+		// o primeiro evento é o estado atual; depois dele o stream é encerrado pelo handle
+		stop := errors.New("primeiro evento recebido")
+		err = submitter.WatchSubmission(ctx, enqueued.Token, func(event client.ProgressEvent) error {
+			if event.SubmissionID != enqueued.Token {
+				t.Errorf("evento de outra submissão: %q", event.SubmissionID)
+			}
+			return stop
+		})
+		if !errors.Is(err, stop) {
+			t.Errorf("WatchSubmission: %v", err)
+		}
+		checkFirstEvent(t, doc, server.URL, enqueued.Token)
+
+		err = submitter.WatchSubmission(ctx, "inexistente", func(client.ProgressEvent) error { return nil })
+		expectAPIError(t, err, http.StatusNotFound)
+	})
+
+	t.Run("lotes", func(t *testing.T) {
+		batch, err := submitter.SubmitBatch(ctx, []client.SubmissionRequest{
+			{ProblemID: "soma", Language: "python", Code: "print(3)"},
+			{ProblemID: "soma", Language: "python", Code: "print(4)"},
+		})
+		if err != nil {
+			t.Fatalf("SubmitBatch: %v", err)
+		}
+		checkDecoded(t, transport, batch)
+
+		_, err = submitter.SubmitBatch(ctx, nil)
+		expectAPIError(t, err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+
+		status, err := submitter.GetBatch(ctx, batch.BatchID)
+		if err != nil {
+			t.Fatalf("GetBatch: %v", err)
+		}
+		checkDecoded(t, transport, status)
+		if status.Total != 2 {
+			t.Errorf("lote com %d itens, esperado 2", status.Total)
+		}
+
+		_, err = submitter.GetBatch(ctx, "inexistente")
+		expectAPIError(t, err, http.StatusNotFound)
+	})
+
+	t.Run("execuções", func(t *testing.T) {
+		enqueued, err := submitter.Run(ctx, client.RunRequest{Language: "python", Code: "print(input())", Input: "oi\n"})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		checkDecoded(t, transport, enqueued)
+
+		_, err = submitter.Run(ctx, client.RunRequest{Language: "python"})
+		expectAPIError(t, err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+
+		execution, err := submitter.GetRun(ctx, enqueued.Token)
+		if err != nil {
+			t.Fatalf("GetRun: %v", err)
+		}
+		checkDecoded(t, transport, execution)
+
+		_, err = submitter.GetRun(ctx, "inexistente")
+		expectAPIError(t, err, http.StatusNotFound)
+
+		// uma submissão corrigida não é uma execução, mesmo existindo
+		graded, err := submitter.Submit(ctx, client.SubmissionRequest{ProblemID: "soma", Language: "python", Code: "print(3)"})
+		if err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+		_, err = submitter.GetRun(ctx, graded.Token)
+		expectAPIError(t, err, http.StatusNotFound)
+	})
+
+	t.Run("callbacks", func(t *testing.T) {
+		callbacks, err := admin.ListCallbacks(ctx, "all", 10)
+		if err != nil {
+			t.Fatalf("ListCallbacks: %v", err)
+		}
+		checkDecoded(t, transport, struct {
+			Callbacks []client.CallbackDelivery `json:"callbacks"`
+		}{callbacks})
+
+		_, err = admin.ListCallbacks(ctx, "desconhecido", 0)
+		expectAPIError(t, err, http.StatusBadRequest)
+
+		_, err = submitter.ListCallbacks(ctx, "", 0)
+		expectAPIError(t, err, http.StatusForbidden)
+
+		err = admin.RetryCallback(ctx, 999999)
+		expectAPIError(t, err, http.StatusNotFound)
+	})
+
+	t.Run("remoções", func(t *testing.T) {
+		if err := admin.InvalidateProblem(ctx, "soma"); err != nil {
+			t.Errorf("InvalidateProblem: %v", err)
+		}
+		expectAPIError(t, admin.InvalidateProblem(ctx, "soma"), http.StatusNotFound)
+
+		if err := admin.RevokeAPIKey(ctx, submitterKeyID); err != nil {
+			t.Errorf("RevokeAPIKey: %v", err)
+		}
+		expectAPIError(t, admin.RevokeAPIKey(ctx, submitterKeyID), http.StatusNotFound)
+	})
+
+	// toda operação documentada precisa ter sido exercitada contra uma rota real
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			if !transport.covered(method + " " + path) {
+				t.Errorf("operação documentada sem resposta conferida: %s %s", method, path)
+			}
+		}
+	}
+}
+
+// loadSpec carrega o openapi.json com os servers, relativos no documento, apontando para baseURL.
+func loadSpec(t *testing.T, baseURL string) *openapi3.T {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromFile("openapi.json")
+	if err != nil {
+		t.Fatalf("especificação OpenAPI inválida: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("especificação OpenAPI inválida: %v", err)
+	}
+
+	for _, server := range doc.Servers {
+		server.URL = baseURL + strings.TrimSuffix(server.URL, "/")
+	}
+	for _, item := range doc.Paths.Map() {
+		for _, server := range item.Servers {
+			server.URL = baseURL + strings.TrimSuffix(server.URL, "/")
+		}
+		// o gorillamux mantém os servers de um path nos seguintes quando eles não declaram os seus
+		if len(item.Servers) == 0 {
+			item.Servers = doc.Servers
+		}
+	}
+	return doc
+}
+
+// startJudger monta as rotas reais com banco, cache e diretórios temporários e a autenticação ligada.
+func startJudger(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite", filepath.Join(dir, "judger.db"))
+	if err != nil {
+		t.Fatalf("abrir banco: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	callbackSink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(callbackSink.Close)
+
+	mux, _, shutdown := router.StartRoutes(&config.Config{
+		APIUrl:                  "http://127.0.0.1:1/problemas",
+		CallbackUrl:             callbackSink.URL,
+		RunTimeLimit:            5 * time.Second,
+		RunMemoryLimitMB:        256,
+		RunOutputLimitKB:        64,
+		AuthEnabled:             true,
+		BootstrapAPIKey:         bootstrapKey,
+		CacheDirectory:          filepath.Join(dir, "cache"),
+		CacheFileExtension:      "-problem",
+		ExecutionDirectory:      filepath.Join(dir, "executions"),
+		RunnerBinaryPath:        filepath.Join(dir, "runner"),
+		OnlyLocalCache:          true,
+		CacheRevalidateInterval: time.Hour,
+		CacheAPITimeout:         5 * time.Second,
+		ProblemPackageMaxMB:     1,
+		ProblemFileMaxMB:        1,
+		ProblemExtractedMaxMB:   4,
+		ContainerTimeout:        10 * time.Second,
+		LeaseTimeout:            time.Minute,
+		JobMaxAttempts:          3,
+		IdempotencyTTL:          time.Hour,
+		ReaperInterval:          time.Minute,
+		MaxWorkers:              1,
+		QueueSize:               100,
+		BatchMaxSize:            10,
+		ReadyQueuePercent:       90,
+		ReadyCheckTimeout:       time.Second,
+		LaneWeights:             map[string]int{"contest": 6, "practice": 3, "run": 3, "rejudge": 1},
+		LaneReservedWorkers:     map[string]int{},
+		CallbackMaxAttempts:     3,
+		CallbackBaseBackoff:     time.Second,
+		CallbackMaxBackoff:      time.Minute,
+		CallbackTimeout:         time.Second,
+	}, db)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Close()
+		shutdown(ctx)
+	})
+	return server
+}
+
+// specTransport confere cada resposta com a operação correspondente da especificação e guarda o
+// último corpo lido, para checkDecoded.
+type specTransport struct {
+	t      *testing.T
+	router routers.Router
+
+	mu         sync.Mutex
+	operations map[string]bool
+	lastBody   []byte
+}
+
+func newSpecTransport(t *testing.T, doc *openapi3.T) *specTransport {
+	specRouter, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatalf("montar roteador da especificação: %v", err)
+	}
+	return &specTransport{t: t, router: specRouter, operations: map[string]bool{}}
+}
+
+func (s *specTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	route, pathParams, err := s.router.FindRoute(req)
+	if err != nil {
+		s.t.Errorf("%s %s: rota fora da especificação: %v", req.Method, req.URL.Path, err)
+		return resp, nil
+	}
+	s.mu.Lock()
+	s.operations[route.Method+" "+route.Path] = true
+	s.mu.Unlock()
+
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status:  resp.StatusCode,
+		Header:  resp.Header,
+		Options: &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+	}
+
+	// o stream SSE só termina com o job; os eventos são conferidos em checkFirstEvent
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		input.Options.ExcludeResponseBody = true
+		if err := openapi3filter.ValidateResponse(req.Context(), input); err != nil {
+			s.t.Errorf("%s %s -> %d: %v", req.Method, req.URL.Path, resp.StatusCode, err)
+		}
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	input.SetBodyBytes(body)
+
+	if err := openapi3filter.ValidateResponse(req.Context(), input); err != nil {
+		s.t.Errorf("%s %s -> %d: %v\n%s", req.Method, req.URL.Path, resp.StatusCode, err, body)
+	}
+
+	s.mu.Lock()
+	s.lastBody = body
+	s.mu.Unlock()
+	return resp, nil
+}
+
+func (s *specTransport) covered(operation string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.operations[operation]
+}
+
+func (s *specTransport) body() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastBody
+}
+
+// checkDecoded confere que o último corpo recebido decodifica no tipo do cliente sem campos
+// desconhecidos e dá o mesmo valor que o cliente devolveu.
+func checkDecoded[T any](t *testing.T, transport *specTransport, got T) {
+	t.Helper()
+
+	var strict T
+	decoder := json.NewDecoder(bytes.NewReader(transport.body()))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&strict); err != nil {
+		t.Errorf("o cliente não conhece todos os campos de %T: %v\n%s", got, err, transport.body())
+		return
+	}
+	if !reflect.DeepEqual(strict, got) {
+		t.Errorf("o cliente decodificou %T diferente do corpo:\ncliente: %+v\ncorpo:   %+v", got, got, strict)
+	}
+}
+
+// checkFirstEvent lê o primeiro evento SSE da submissão e confere o JSON com o schema ProgressEvent.
+func checkFirstEvent(t *testing.T, doc *openapi3.T, baseURL, token string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/v1/job/"+token+"/events", nil)
+	req.Header.Set("Authorization", "Bearer "+bootstrapKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /v1/job/{id}/events: %v", err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		var event any
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			t.Fatalf("evento SSE não é JSON: %v", err)
+		}
+		if err := doc.Components.Schemas["ProgressEvent"].Value.VisitJSON(event); err != nil {
+			t.Errorf("evento SSE fora do schema ProgressEvent: %v\n%s", err, data)
+		}
+		return
+	}
+	t.Errorf("stream SSE terminou sem eventos: %v", scanner.Err())
+}
+
+func expectAPIError(t *testing.T, err error, statuses ...int) {
+	t.Helper()
+
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("esperado *client.APIError com status %v, veio %v", statuses, err)
+		return
+	}
+	if !slices.Contains(statuses, apiErr.StatusCode) {
+		t.Errorf("status %d, esperado um de %v: %v", apiErr.StatusCode, statuses, apiErr)
+	}
+	if apiErr.Code == "" || apiErr.Message == "" {
+		t.Errorf("envelope de erro incompleto: %+v", apiErr)
+	}
+}
+
+func problemPackage(t *testing.T, input string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := map[string]string{
+		"meta.json": `[{"language":"python","time_limit":1,"memory_limit":256}]`,
+		"1.in":      input,
+		"1.out":     "3\n",
+	}
+	for name, content := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, content)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
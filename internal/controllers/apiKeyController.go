@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"IFJudger/internal/api/apierror"
+	"IFJudger/internal/api/dto"
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type APIKeyController struct {
+	authService *services.AuthService
+}
+
+func StartAPIKeyController(authService *services.AuthService) (*APIKeyController, error) {
+	return &APIKeyController{
+		authService: authService,
+	}, nil
+}
+
+func (c *APIKeyController) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateAPIKeyRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "Invalid JSON format", nil)
+		return
+	}
+	defer r.Body.Close()
+
+	key, rawKey, err := c.authService.CreateKey(req.Name, req.Scopes, req.RateLimitPerMinute, req.MaxActiveJobs)
+	if err != nil {
+		apierror.WriteError(w, err)
+		return
+	}
+
+	response := dto.CreateAPIKeyResponseDTO{
+		APIKeyDTO: toAPIKeyDTO(key),
+		Key:       rawKey,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (c *APIKeyController) HandleList(w http.ResponseWriter, r *http.Request) {
+	keys, err := c.authService.ListKeys()
+	if err != nil {
+		apierror.WriteError(w, err)
+		return
+	}
+
+	response := dto.APIKeyListResponseDTO{
+		Keys: make([]dto.APIKeyDTO, len(keys)),
+	}
+	for i, key := range keys {
+		response.Keys[i] = toAPIKeyDTO(key)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (c *APIKeyController) HandleRevoke(w http.ResponseWriter, r *http.Request) {
+	if err := c.authService.RevokeKey(r.PathValue("id")); err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "API key not found or already revoked", nil)
+			return
+		}
+		apierror.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toAPIKeyDTO(key models.APIKey) dto.APIKeyDTO {
+	return dto.APIKeyDTO{
+		ID:                 key.ID,
+		Name:               key.Name,
+		Scopes:             key.Scopes,
+		RateLimitPerMinute: key.RateLimitPerMinute,
+		MaxActiveJobs:      key.MaxActiveJobs,
+		CreatedAt:          key.CreatedAt,
+		RevokedAt:          key.RevokedAt,
+	}
+}
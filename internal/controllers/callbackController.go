@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"IFJudger/internal/api/apierror"
+	"IFJudger/internal/api/dto"
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+type CallbackController struct {
+	callbackService *services.CallbackService
+}
+
+const (
+	defaultCallbackListLimit = 50
+	maxCallbackListLimit     = 500
+)
+
+func StartCallbackController(callbackService *services.CallbackService) (*CallbackController, error) {
+	return &CallbackController{
+		callbackService: callbackService,
+	}, nil
+}
+
+// HandleList lista as entregas de callback; por padrão apenas as que falharam.
+func (c *CallbackController) HandleList(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = "failed"
+	}
+	switch status {
+	case "all":
+		status = ""
+	case models.CallbackPending, models.CallbackDelivered, models.CallbackFailed:
+	default:
+		writeInvalidParameter(w, "status", "Invalid 'status' query parameter")
+		return
+	}
+
+	limit := defaultCallbackListLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeInvalidParameter(w, "limit", "Invalid 'limit' query parameter")
+			return
+		}
+		limit = min(parsed, maxCallbackListLimit)
+	}
+
+	deliveries, err := c.callbackService.ListDeliveries(status, limit)
+	if err != nil {
+		apierror.WriteError(w, err)
+		return
+	}
+
+	response := dto.CallbackListResponseDTO{
+		Callbacks: make([]dto.CallbackDeliveryDTO, len(deliveries)),
+	}
+	for i, d := range deliveries {
+		response.Callbacks[i] = dto.CallbackDeliveryDTO{
+			ID:            d.ID,
+			SubmissionID:  d.SubmissionID,
+			URL:           d.URL,
+			Status:        d.Status,
+			Attempts:      d.Attempts,
+			NextAttemptAt: d.NextAttemptAt,
+			LastError:     d.LastError,
+			CreatedAt:     d.CreatedAt,
+			UpdatedAt:     d.UpdatedAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (c *CallbackController) HandleRetry(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeInvalidParameter(w, "id", "Invalid callback id")
+		return
+	}
+
+	if err := c.callbackService.Retry(id); err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "Callback not found or already delivered", nil)
+			return
+		}
+		apierror.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"IFJudger/internal/api/apierror"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// missingFields devolve, em ordem alfabética, os campos obrigatórios que vieram vazios.
+func missingFields(fields map[string]string) []string {
+	var missing []string
+	for name, value := range fields {
+		if value == "" {
+			missing = append(missing, name)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+func writeMissingFields(w http.ResponseWriter, missing []string) {
+	apierror.Write(w, http.StatusUnprocessableEntity, apierror.CodeValidation,
+		"Missing required fields ("+strings.Join(missing, ", ")+")",
+		map[string]any{"missing_fields": missing})
+}
+
+func writeInvalidParameter(w http.ResponseWriter, parameter, message string) {
+	apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidParameter, message, map[string]any{"parameter": parameter})
+}
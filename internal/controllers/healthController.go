@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"IFJudger/internal/api/dto"
+	"IFJudger/internal/models"
+	"IFJudger/internal/services"
+	"encoding/json"
+	"net/http"
+)
+
+type HealthController struct {
+	healthService *services.HealthService
+}
+
+func StartHealthController(healthService *services.HealthService) (*HealthController, error) {
+	return &HealthController{
+		healthService: healthService,
+	}, nil
+}
+
+// HandleLiveness responde enquanto o processo estiver de pé; não verifica dependências.
+func (c *HealthController) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.HealthResponseDTO{Status: models.HealthOK})
+}
+
+// HandleReadiness responde 200 quando todas as dependências estão ok e 503 caso contrário,
+// sempre com o estado de cada componente.
+func (c *HealthController) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	report := c.healthService.Readiness(r.Context())
+
+	response := dto.HealthResponseDTO{
+		Status:     models.HealthOK,
+		Components: make(map[string]dto.ComponentHealthDTO, len(report.Components)),
+	}
+	for _, component := range report.Components {
+		response.Components[component.Name] = dto.ComponentHealthDTO{
+			Status:    component.Status,
+			Message:   component.Message,
+			LatencyMS: component.LatencyMS,
+		}
+	}
+
+	status := http.StatusOK
+	if !report.Ready {
+		response.Status = models.HealthFail
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
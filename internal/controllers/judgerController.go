@@ -1,10 +1,19 @@
 package controllers
 
 import (
+	"IFJudger/internal/api/apierror"
 	"IFJudger/internal/api/dto"
+	"IFJudger/internal/middleware"
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
 	"IFJudger/internal/services"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type JudgerController struct {
@@ -18,27 +27,41 @@ func StartJudgerController(judgerService *services.JudgerService) (*JudgerContro
 }
 
 func (c *JudgerController) HandleSubmission(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get(idempotencyKeyHeader)
+	if !validIdempotencyKey(idempotencyKey) {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidParameter,
+			"Invalid Idempotency-Key header (up to 255 printable ASCII characters)", map[string]any{"header": idempotencyKeyHeader})
+		return
+	}
+
 	var req dto.SubmissionRequestDTO
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "Invalid JSON format", nil)
 		return
 	}
 	defer r.Body.Close()
 
-	if req.ProblemID == "" || req.Code == "" || req.Language == "" {
-		http.Error(w, "Missing required fields (problem_id, code, language)", http.StatusBadRequest)
+	if missing := missingFields(map[string]string{"problem_id": req.ProblemID, "code": req.Code, "language": req.Language}); len(missing) > 0 {
+		writeMissingFields(w, missing)
 		return
 	}
 
 	serviceRequest := dto.JudgeRequest{
-		ProblemID:     req.ProblemID,
-		LanguageToken: req.Language,
-		Code:          req.Code,
+		ProblemID:      req.ProblemID,
+		LanguageToken:  req.Language,
+		Code:           req.Code,
+		Priority:       req.Priority,
+		WebhookURL:     req.WebhookURL,
+		IdempotencyKey: idempotencyKey,
+	}
+	if key, ok := middleware.APIKeyFromContext(r.Context()); ok {
+		serviceRequest.ClientID = key.ID
+		serviceRequest.ClientQuota = key.MaxActiveJobs
 	}
 
-	token, err := c.judgerService.EnqueueJudge(serviceRequest)
+	token, replayed, err := c.judgerService.EnqueueJudge(r.Context(), serviceRequest)
 	if err != nil {
-		http.Error(w, "Failed to enqueue submission: "+err.Error(), http.StatusInternalServerError)
+		apierror.WriteError(w, err)
 		return
 	}
 
@@ -46,26 +69,49 @@ func (c *JudgerController) HandleSubmission(w http.ResponseWriter, r *http.Reque
 		Token:   token,
 		Message: "Submission enqueued successfully",
 	}
+	if replayed {
+		response.Message = "Submission already enqueued with this Idempotency-Key"
+		w.Header().Set(idempotentReplayedHeader, "true")
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// validIdempotencyKey aceita a chave ausente ou com até 255 caracteres ASCII visíveis.
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *JudgerController) HandleStatus(w http.ResponseWriter, r *http.Request) {
 	token := r.URL.Query().Get("token")
 	if token == "" {
-		http.Error(w, "Missing 'token' query parameter", http.StatusBadRequest)
+		writeInvalidParameter(w, "token", "Missing 'token' query parameter")
 		return
 	}
 
 	jobResult, err := c.judgerService.GetResult(token)
 	if err != nil {
-		if err.Error() == "job does not exist" {
-			http.Error(w, "Submission not found", http.StatusNotFound)
+		if errors.Is(err, customErrors.ErrNotFound) {
+			apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "Submission not found", nil)
 			return
 		}
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apierror.WriteError(w, err)
 		return
 	}
 
@@ -80,3 +126,398 @@ func (c *JudgerController) HandleStatus(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// tamanho máximo do corpo do POST /run (código + entrada)
+const maxRunRequestBytes = 2 << 20
+
+func (c *JudgerController) HandleRun(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRunRequestBytes)
+	defer r.Body.Close()
+
+	var req dto.ExecutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			apierror.Write(w, http.StatusRequestEntityTooLarge, apierror.CodePayloadTooLarge, "Request body too large", map[string]any{"limit_bytes": maxBytesErr.Limit})
+			return
+		}
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "Invalid JSON format", nil)
+		return
+	}
+
+	if missing := missingFields(map[string]string{"code": req.Code, "language": req.Language}); len(missing) > 0 {
+		writeMissingFields(w, missing)
+		return
+	}
+
+	runRequest := dto.RunRequest{
+		LanguageToken: req.Language,
+		Code:          req.Code,
+		Input:         req.Input,
+		WebhookURL:    req.WebhookURL,
+	}
+	if key, ok := middleware.APIKeyFromContext(r.Context()); ok {
+		runRequest.ClientID = key.ID
+		runRequest.ClientQuota = key.MaxActiveJobs
+	}
+
+	token, err := c.judgerService.EnqueueRun(r.Context(), runRequest)
+	if err != nil {
+		apierror.WriteError(w, err)
+		return
+	}
+
+	response := dto.ExecutionEnqueuedResponse{
+		Token:   token,
+		Message: "Execution enqueued successfully",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (c *JudgerController) HandleRunStatus(w http.ResponseWriter, r *http.Request) {
+	jobResult, err := c.judgerService.GetRun(r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "Execution not found", nil)
+			return
+		}
+		apierror.WriteError(w, err)
+		return
+	}
+
+	response := dto.ExecutionResponse{
+		ID:     jobResult.ID,
+		Status: jobResult.Status,
+		Error:  jobResult.ErrorMessage,
+	}
+	if run := jobResult.Result.Run; run != nil {
+		response.RunStatus = run.Status
+		response.Stdout = run.Stdout
+		response.Stderr = run.Stderr
+		response.Truncated = run.Truncated
+		response.ExitCode = run.ExitCode
+		response.TimeMS = run.TimeMS
+		response.MemoryKB = run.MemoryKB
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleLanguages lista as linguagens aceitas, com a imagem e os comandos usados para executá-las.
+func (c *JudgerController) HandleLanguages(w http.ResponseWriter, r *http.Request) {
+	languages := c.judgerService.ListLanguages()
+
+	response := dto.LanguageListResponseDTO{
+		Languages: make([]dto.LanguageDTO, len(languages)),
+	}
+	for i, language := range languages {
+		response.Languages[i] = toLanguageDTO(language)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleProblemLanguages lista as linguagens que o problema permite, com os limites efetivos de cada uma.
+func (c *JudgerController) HandleProblemLanguages(w http.ResponseWriter, r *http.Request) {
+	problemID := r.PathValue("id")
+
+	languages, err := c.judgerService.ListProblemLanguages(r.Context(), problemID)
+	if err != nil {
+		apierror.WriteError(w, err)
+		return
+	}
+
+	response := dto.ProblemLanguagesResponseDTO{
+		ProblemID: problemID,
+		Languages: make([]dto.ProblemLanguageDTO, len(languages)),
+	}
+	for i, language := range languages {
+		response.Languages[i] = dto.ProblemLanguageDTO{
+			LanguageDTO:      toLanguageDTO(language.Language),
+			TimeLimitSeconds: language.Limits.TimeLimitSeconds,
+			MemoryLimitMB:    language.Limits.MaximumRamMB,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func toLanguageDTO(language models.Language) dto.LanguageDTO {
+	return dto.LanguageDTO{
+		Token:          language.Token,
+		Name:           language.Name,
+		Version:        language.Version,
+		Image:          language.Image,
+		FileExtension:  language.FileExtension,
+		CompileCommand: models.FormatCommand(language.CompileCommand),
+		RunCommand:     models.FormatCommand(language.RunCommand),
+	}
+}
+
+const (
+	defaultSubmissionListLimit = 50
+	maxSubmissionListLimit     = 500
+)
+
+// HandleList busca submissões com filtros de status, problema, linguagem, prioridade e período.
+// Parâmetros: status, problem_id, language, priority, from e to (RFC 3339), order (asc|desc), cursor e limit.
+func (c *JudgerController) HandleList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := models.SubmissionFilter{
+		Status:     query.Get("status"),
+		ProblemID:  query.Get("problem_id"),
+		Language:   query.Get("language"),
+		Priority:   query.Get("priority"),
+		Cursor:     query.Get("cursor"),
+		Descending: true,
+		Limit:      defaultSubmissionListLimit,
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		filter.Descending = false
+	default:
+		writeInvalidParameter(w, "order", "Invalid 'order' query parameter (asc or desc)")
+		return
+	}
+
+	for param, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		raw := query.Get(param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeInvalidParameter(w, param, "Invalid '"+param+"' query parameter, expected RFC 3339")
+			return
+		}
+		*target = parsed
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeInvalidParameter(w, "limit", "Invalid 'limit' query parameter")
+			return
+		}
+		filter.Limit = min(parsed, maxSubmissionListLimit)
+	}
+
+	page, err := c.judgerService.ListSubmissions(filter)
+	if err != nil {
+		apierror.WriteError(w, err)
+		return
+	}
+
+	response := dto.SubmissionListResponseDTO{
+		Submissions: make([]dto.SubmissionSummaryDTO, len(page.Submissions)),
+		NextCursor:  page.NextCursor,
+	}
+	for i, sub := range page.Submissions {
+		response.Submissions[i] = dto.SubmissionSummaryDTO{
+			ID:           sub.ID,
+			ProblemID:    sub.ProblemID,
+			Language:     sub.Language,
+			Priority:     sub.Priority,
+			Status:       sub.Status,
+			ErrorMessage: sub.ErrorMessage,
+			UpdatedAt:    sub.UpdatedAt,
+		}
+		if !sub.CreatedAt.IsZero() {
+			createdAt := sub.CreatedAt
+			response.Submissions[i].CreatedAt = &createdAt
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// intervalo dos comentários de keepalive no stream SSE; também é quando o estado salvo é
+// conferido, cobrindo jobs executados por outra instância do judger
+const sseKeepAliveInterval = 15 * time.Second
+
+// HandleEvents transmite o progresso da submissão via Server-Sent Events até o resultado final.
+func (c *JudgerController) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("id")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "Streaming not supported", nil)
+		return
+	}
+
+	snapshot, events, unsubscribe, err := c.judgerService.WatchResult(token)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "Submission not found", nil)
+			return
+		}
+		apierror.WriteError(w, err)
+		return
+	}
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	current := models.ProgressFromResult(snapshot)
+	writeSSE(w, current)
+	flusher.Flush()
+	if current.IsFinal() {
+		return
+	}
+
+	ticker := time.NewTicker(sseKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case event := <-events:
+			writeSSE(w, event)
+			flusher.Flush()
+			if event.IsFinal() {
+				return
+			}
+
+		case <-ticker.C:
+			if result, err := c.judgerService.GetResult(token); err == nil {
+				if latest := models.ProgressFromResult(result); latest.IsFinal() {
+					writeSSE(w, latest)
+					flusher.Flush()
+					return
+				}
+			}
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, event models.ProgressEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+}
+
+// tamanho máximo do corpo do POST /submit/batch
+const maxBatchRequestBytes = 16 << 20
+
+// HandleBatchSubmission enfileira várias submissões de uma vez. O lote é aceito ou recusado por inteiro:
+// se alguma submissão for inválida, a resposta é 422 com o erro de cada uma em details.items.
+func (c *JudgerController) HandleBatchSubmission(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchRequestBytes)
+	defer r.Body.Close()
+
+	var req dto.BatchRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			apierror.Write(w, http.StatusRequestEntityTooLarge, apierror.CodePayloadTooLarge, "Request body too large", map[string]any{"limit_bytes": maxBytesErr.Limit})
+			return
+		}
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "Invalid JSON format", nil)
+		return
+	}
+
+	batchRequest := dto.BatchJudgeRequest{
+		Submissions: make([]dto.JudgeRequest, len(req.Submissions)),
+	}
+	if key, ok := middleware.APIKeyFromContext(r.Context()); ok {
+		batchRequest.ClientID = key.ID
+		batchRequest.ClientQuota = key.MaxActiveJobs
+	}
+
+	var batchErr customErrors.BatchError
+	for i, sub := range req.Submissions {
+		if missing := missingFields(map[string]string{"problem_id": sub.ProblemID, "code": sub.Code, "language": sub.Language}); len(missing) > 0 {
+			batchErr.Items = append(batchErr.Items, customErrors.BatchItemError{
+				Index: i,
+				Err:   fmt.Errorf("%w: missing required fields (%s)", customErrors.ErrValidation, strings.Join(missing, ", ")),
+			})
+			continue
+		}
+
+		batchRequest.Submissions[i] = dto.JudgeRequest{
+			ProblemID:     sub.ProblemID,
+			LanguageToken: sub.Language,
+			Code:          sub.Code,
+			Priority:      sub.Priority,
+			WebhookURL:    sub.WebhookURL,
+		}
+	}
+	if len(batchErr.Items) > 0 {
+		apierror.WriteError(w, &batchErr)
+		return
+	}
+
+	batchID, tokens, err := c.judgerService.EnqueueBatch(r.Context(), batchRequest)
+	if err != nil {
+		apierror.WriteError(w, err)
+		return
+	}
+
+	response := dto.BatchResponseDTO{
+		BatchID: batchID,
+		Tokens:  tokens,
+		Message: "Batch enqueued successfully",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (c *JudgerController) HandleBatchStatus(w http.ResponseWriter, r *http.Request) {
+	batch, err := c.judgerService.GetBatch(r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "Batch not found", nil)
+			return
+		}
+		apierror.WriteError(w, err)
+		return
+	}
+
+	response := dto.BatchStatusResponseDTO{
+		ID:        batch.ID,
+		Total:     batch.Total,
+		Counts:    batch.Counts,
+		Done:      batch.Done(),
+		CreatedAt: batch.CreatedAt,
+		Items:     make([]dto.BatchItemDTO, len(batch.Items)),
+	}
+	for i, item := range batch.Items {
+		response.Items[i] = dto.BatchItemDTO{
+			Index:        item.Index,
+			Token:        item.ID,
+			ProblemID:    item.ProblemID,
+			Status:       item.Status,
+			ErrorMessage: item.ErrorMessage,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
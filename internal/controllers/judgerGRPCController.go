@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"IFJudger/internal/api/dto"
+	"IFJudger/internal/middleware"
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/internal/services"
+	"IFJudger/pkg/judgerpb"
+	"IFJudger/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// JudgerGRPCController expõe o JudgerService pela API gRPC definida em pkg/judgerpb/judger.proto.
+type JudgerGRPCController struct {
+	judgerpb.UnimplementedJudgerServer
+	judgerService *services.JudgerService
+}
+
+func StartJudgerGRPCController(judgerService *services.JudgerService) (*JudgerGRPCController, error) {
+	return &JudgerGRPCController{
+		judgerService: judgerService,
+	}, nil
+}
+
+func (c *JudgerGRPCController) Submit(ctx context.Context, req *judgerpb.SubmitRequest) (*judgerpb.SubmitResponse, error) {
+	if missing := missingFields(map[string]string{"problem_id": req.GetProblemId(), "code": req.GetCode(), "language": req.GetLanguage()}); len(missing) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "Missing required fields ("+strings.Join(missing, ", ")+")")
+	}
+	if !validIdempotencyKey(req.GetIdempotencyKey()) {
+		return nil, status.Error(codes.InvalidArgument, "Invalid idempotency_key (up to 255 printable ASCII characters)")
+	}
+
+	serviceRequest := dto.JudgeRequest{
+		ProblemID:      req.GetProblemId(),
+		LanguageToken:  req.GetLanguage(),
+		Code:           req.GetCode(),
+		Priority:       req.GetPriority(),
+		WebhookURL:     req.GetWebhookUrl(),
+		IdempotencyKey: req.GetIdempotencyKey(),
+	}
+	if key, ok := middleware.APIKeyFromContext(ctx); ok {
+		serviceRequest.ClientID = key.ID
+		serviceRequest.ClientQuota = key.MaxActiveJobs
+	}
+
+	token, replayed, err := c.judgerService.EnqueueJudge(ctx, serviceRequest)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	return &judgerpb.SubmitResponse{Token: token, Replayed: replayed}, nil
+}
+
+func (c *JudgerGRPCController) GetStatus(ctx context.Context, req *judgerpb.GetStatusRequest) (*judgerpb.Submission, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Missing token")
+	}
+
+	jobResult, err := c.judgerService.GetResult(req.GetToken())
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	return toSubmissionPB(jobResult), nil
+}
+
+// WatchSubmission transmite o progresso da submissão como o GET /job/{id}/events: o estado atual
+// primeiro, depois cada evento, até completed ou failed.
+func (c *JudgerGRPCController) WatchSubmission(req *judgerpb.WatchSubmissionRequest, stream grpc.ServerStreamingServer[judgerpb.ProgressEvent]) error {
+	ctx := stream.Context()
+	token := req.GetToken()
+	if token == "" {
+		return status.Error(codes.InvalidArgument, "Missing token")
+	}
+
+	snapshot, events, unsubscribe, err := c.judgerService.WatchResult(token)
+	if err != nil {
+		return grpcError(ctx, err)
+	}
+	defer unsubscribe()
+
+	current := models.ProgressFromResult(snapshot)
+	if err := stream.Send(toProgressEventPB(current)); err != nil || current.IsFinal() {
+		return err
+	}
+
+	// sem keepalive a enviar, o ticker só confere o estado salvo, para jobs executados por outra instância
+	ticker := time.NewTicker(sseKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event := <-events:
+			if err := stream.Send(toProgressEventPB(event)); err != nil || event.IsFinal() {
+				return err
+			}
+
+		case <-ticker.C:
+			if result, err := c.judgerService.GetResult(token); err == nil {
+				if latest := models.ProgressFromResult(result); latest.IsFinal() {
+					return stream.Send(toProgressEventPB(latest))
+				}
+			}
+		}
+	}
+}
+
+func (c *JudgerGRPCController) Cancel(ctx context.Context, req *judgerpb.CancelRequest) (*judgerpb.Submission, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Missing token")
+	}
+
+	// só a chave que enviou a submissão pode cancelá-la; a admin cancela qualquer uma
+	var clientID string
+	if key, ok := middleware.APIKeyFromContext(ctx); ok && !key.HasScope(models.ScopeAdmin) {
+		clientID = key.ID
+	}
+
+	jobResult, err := c.judgerService.Cancel(ctx, req.GetToken(), clientID)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	return toSubmissionPB(jobResult), nil
+}
+
+func (c *JudgerGRPCController) ListLanguages(ctx context.Context, req *judgerpb.ListLanguagesRequest) (*judgerpb.ListLanguagesResponse, error) {
+	languages := c.judgerService.ListLanguages()
+
+	response := &judgerpb.ListLanguagesResponse{
+		Languages: make([]*judgerpb.Language, len(languages)),
+	}
+	for i, language := range languages {
+		response.Languages[i] = &judgerpb.Language{
+			Token:          language.Token,
+			Name:           language.Name,
+			Version:        language.Version,
+			Image:          language.Image,
+			FileExtension:  language.FileExtension,
+			CompileCommand: models.FormatCommand(language.CompileCommand),
+			RunCommand:     models.FormatCommand(language.RunCommand),
+		}
+	}
+	return response, nil
+}
+
+// grpcError traduz um erro dos serviços para o status gRPC correspondente, como o
+// apierror.WriteError faz para a API HTTP.
+func grpcError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, customErrors.ErrValidation),
+		errors.Is(err, customErrors.ErrInvalidLanguage),
+		errors.Is(err, customErrors.ErrInvalidWebhookURL),
+		errors.Is(err, customErrors.ErrInvalidFilter),
+		errors.Is(err, customErrors.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, customErrors.ErrIdempotencyConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, customErrors.ErrNotCancelable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, customErrors.ErrProblemNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, customErrors.ErrNotFound):
+		return status.Error(codes.NotFound, "Submission not found")
+	case errors.Is(err, customErrors.ErrUnauthorized):
+		return status.Error(codes.Unauthenticated, "Missing or invalid API key")
+	case errors.Is(err, customErrors.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, customErrors.ErrQueueFull), errors.Is(err, customErrors.ErrShuttingDown):
+		grpc.SetHeader(ctx, metadata.Pairs("retry-after", "10"))
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		logger.Component("grpc").Error("Erro interno ao atender chamada", logger.Err(err))
+		return status.Error(codes.Internal, "Internal server error")
+	}
+}
+
+func toSubmissionPB(jobResult models.JobResult) *judgerpb.Submission {
+	return &judgerpb.Submission{
+		Id:     jobResult.ID,
+		Status: jobResult.Status,
+		Result: toExecutionReportPB(&jobResult.Result),
+		Error:  jobResult.ErrorMessage,
+	}
+}
+
+func toProgressEventPB(event models.ProgressEvent) *judgerpb.ProgressEvent {
+	response := &judgerpb.ProgressEvent{
+		Type:         event.Type,
+		SubmissionId: event.SubmissionID,
+		Status:       event.Status,
+		Result:       toExecutionReportPB(event.Result),
+		Error:        event.ErrorMessage,
+	}
+	if event.TestCase != nil {
+		response.TestCase = toTestCaseResultPB(*event.TestCase)
+	}
+	return response
+}
+
+func toExecutionReportPB(report *models.ExecutionReport) *judgerpb.ExecutionReport {
+	if report == nil {
+		return nil
+	}
+
+	response := &judgerpb.ExecutionReport{
+		Results: make([]*judgerpb.TestCaseResult, len(report.Results)),
+	}
+	for i, result := range report.Results {
+		response.Results[i] = toTestCaseResultPB(result)
+	}
+	if run := report.Run; run != nil {
+		response.Run = &judgerpb.RunResult{
+			Status:    run.Status,
+			Stdout:    run.Stdout,
+			Stderr:    run.Stderr,
+			Truncated: run.Truncated,
+			ExitCode:  int32(run.ExitCode),
+			TimeMs:    run.TimeMS,
+			MemoryKb:  run.MemoryKB,
+		}
+	}
+	return response
+}
+
+func toTestCaseResultPB(result models.TestCaseResult) *judgerpb.TestCaseResult {
+	return &judgerpb.TestCaseResult{
+		Id:      result.ID,
+		Status:  result.Status,
+		TimeMs:  result.TimeMS,
+		Message: result.Message,
+	}
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"IFJudger/internal/api/apierror"
+	"IFJudger/internal/api/dto"
+	"IFJudger/internal/services"
+	"encoding/json"
+	"net/http"
+)
+
+type ProblemController struct {
+	cacheService *services.CacheService
+}
+
+func StartProblemController(cacheService *services.CacheService) (*ProblemController, error) {
+	return &ProblemController{
+		cacheService: cacheService,
+	}, nil
+}
+
+// HandleUpload recebe o .zip do problema no corpo da requisição e o instala no cache, substituindo
+// a versão anterior. Responde 201 para um problema novo e 200 para uma substituição.
+func (c *ProblemController) HandleUpload(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	pkg, replaced, err := c.cacheService.UploadPackage(r.Context(), r.PathValue("id"), r.Body)
+	if err != nil {
+		apierror.WriteError(w, err)
+		return
+	}
+
+	response := dto.ProblemUploadResponseDTO{
+		ProblemID:   pkg.ProblemID,
+		Version:     pkg.Version,
+		SHA256:      pkg.SHA256,
+		TestCases:   pkg.TestCases,
+		Languages:   make([]dto.ProblemPackageLanguageDTO, len(pkg.Languages)),
+		InstalledAt: pkg.InstalledAt,
+		Replaced:    replaced,
+	}
+	for i, limits := range pkg.Languages {
+		response.Languages[i] = dto.ProblemPackageLanguageDTO{
+			Language:         limits.Name,
+			TimeLimitSeconds: limits.TimeLimitSeconds,
+			MemoryLimitMB:    limits.MaximumRamMB,
+		}
+	}
+
+	for _, issue := range pkg.Warnings {
+		response.Warnings = append(response.Warnings, dto.PackageIssueDTO{
+			Code:    issue.Code,
+			File:    issue.File,
+			Message: issue.Message,
+		})
+	}
+
+	status := http.StatusCreated
+	if replaced {
+		status = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleInvalidate tira o problema do cache, para o backend chamar depois de editar o problema:
+// a próxima submissão baixa o pacote atualizado da API.
+func (c *ProblemController) HandleInvalidate(w http.ResponseWriter, r *http.Request) {
+	if err := c.cacheService.Invalidate(r.Context(), r.PathValue("id")); err != nil {
+		apierror.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"IFJudger/internal/api/apierror"
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/internal/services"
+	"IFJudger/pkg/logger"
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type contextKey struct{}
+
+var apiKeyContextKey = contextKey{}
+
+type Auth struct {
+	authService *services.AuthService
+	enabled     bool
+}
+
+func StartAuth(authService *services.AuthService, enabled bool) (*Auth, error) {
+	if !enabled {
+		logger.Component("auth").Warn("AUTH_ENABLED=false: a API está aberta, sem verificação de chaves; as rotas de escopo admin ficam desativadas.")
+	}
+
+	return &Auth{
+		authService: authService,
+		enabled:     enabled,
+	}, nil
+}
+
+// Require só deixa a requisição seguir se ela apresentar uma chave ativa com o escopo pedido,
+// dentro do rate limit da chave. A chave autenticada fica disponível em APIKeyFromContext.
+// Com a autenticação desligada as demais rotas ficam abertas, mas as de escopo admin respondem 403:
+// sem chave não há como saber quem pode subir pacotes ou mexer em chaves e callbacks.
+func (a *Auth) Require(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.enabled {
+			if scope == models.ScopeAdmin {
+				apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "Admin routes require AUTH_ENABLED=true", map[string]any{"required_scope": scope})
+				return
+			}
+			next(w, r)
+			return
+		}
+
+		key, err := a.authService.Authenticate(extractAPIKey(r))
+		if err != nil {
+			if errors.Is(err, customErrors.ErrUnauthorized) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="judger"`)
+				apierror.Write(w, http.StatusUnauthorized, apierror.CodeUnauthorized, "Missing or invalid API key", nil)
+				return
+			}
+			logger.Component("auth").Error("Erro ao validar chave de API", logger.Err(err))
+			apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "Internal server error", nil)
+			return
+		}
+
+		if !key.HasScope(scope) {
+			apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "API key lacks the '"+scope+"' scope", map[string]any{"required_scope": scope})
+			return
+		}
+
+		if allowed, wait := a.authService.Allow(key); !allowed {
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			apierror.Write(w, http.StatusTooManyRequests, apierror.CodeRateLimited, customErrors.ErrRateLimited.Error(), map[string]any{"retry_after_seconds": seconds})
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey, key)))
+	}
+}
+
+// APIKeyFromContext retorna a chave que autenticou a requisição; ok é false com a autenticação desligada.
+func APIKeyFromContext(ctx context.Context) (models.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey).(models.APIKey)
+	return key, ok
+}
+
+// extractAPIKey aceita tanto "Authorization: Bearer <chave>" quanto "X-API-Key: <chave>"
+func extractAPIKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		scheme, token, ok := strings.Cut(auth, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
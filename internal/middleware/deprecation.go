@@ -0,0 +1,13 @@
+package middleware
+
+import "net/http"
+
+// Deprecated marca as respostas dos caminhos sem versão, que continuam funcionando mas
+// foram substituídos pelos equivalentes em successorPrefix (ex.: "/v1").
+func Deprecated(successorPrefix string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successorPrefix+r.URL.Path+`>; rel="successor-version"`)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/pkg/logger"
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryInterceptor aplica às chamadas gRPC as mesmas regras de Require, com o escopo de cada
+// método em scopes (chave: nome completo do método, ex.: "/judger.v1.Judger/Submit").
+func (a *Auth) UnaryInterceptor(scopes map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorizeGRPC(ctx, info.FullMethod, scopes)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor é o equivalente de UnaryInterceptor para os métodos com stream.
+func (a *Auth) StreamInterceptor(scopes map[string]string) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorizeGRPC(stream.Context(), info.FullMethod, scopes)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func (a *Auth) authorizeGRPC(ctx context.Context, method string, scopes map[string]string) (context.Context, error) {
+	scope, ok := scopes[method]
+	if !ok {
+		// um método registrado sem escopo é erro de configuração; negar é mais seguro que liberar
+		logger.Component("auth").Error("Método gRPC sem escopo definido", "method", method)
+		return nil, status.Error(codes.PermissionDenied, "method has no required scope")
+	}
+
+	if !a.enabled {
+		return ctx, nil
+	}
+
+	key, err := a.authService.Authenticate(extractGRPCAPIKey(ctx))
+	if err != nil {
+		if errors.Is(err, customErrors.ErrUnauthorized) {
+			return nil, status.Error(codes.Unauthenticated, "Missing or invalid API key")
+		}
+		logger.Component("auth").Error("Erro ao validar chave de API", logger.Err(err))
+		return nil, status.Error(codes.Internal, "Internal server error")
+	}
+
+	if !key.HasScope(scope) {
+		return nil, status.Error(codes.PermissionDenied, "API key lacks the '"+scope+"' scope")
+	}
+
+	if allowed, wait := a.authService.Allow(key); !allowed {
+		seconds := int(math.Ceil(wait.Seconds()))
+		grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+		return nil, status.Error(codes.ResourceExhausted, customErrors.ErrRateLimited.Error())
+	}
+
+	return context.WithValue(ctx, apiKeyContextKey, key), nil
+}
+
+// extractGRPCAPIKey lê a chave dos metadados "authorization: Bearer <chave>" ou "x-api-key".
+func extractGRPCAPIKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if values := md.Get("authorization"); len(values) > 0 {
+		scheme, token, ok := strings.Cut(values[0], " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	if values := md.Get("x-api-key"); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+// authenticatedStream troca o contexto do stream pelo que carrega a chave autenticada.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
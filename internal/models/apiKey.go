@@ -0,0 +1,31 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Escopos de uma chave de API. ScopeAdmin concede todos os outros.
+const (
+	ScopeSubmit = "submit"
+	ScopeRead   = "read"
+	ScopeAdmin  = "admin"
+)
+
+var Scopes = []string{ScopeSubmit, ScopeRead, ScopeAdmin}
+
+type APIKey struct {
+	ID     string
+	Name   string
+	Scopes []string
+	// RateLimitPerMinute limita as requisições da chave; 0 não limita
+	RateLimitPerMinute int
+	// MaxActiveJobs limita os jobs queued/processing da chave; 0 não limita
+	MaxActiveJobs int
+	CreatedAt     time.Time
+	RevokedAt     *time.Time
+}
+
+func (k APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope) || slices.Contains(k.Scopes, ScopeAdmin)
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Batch agrupa submissões enviadas juntas em POST /submit/batch.
+type Batch struct {
+	ID        string
+	ClientID  string // chave de API que criou o lote; vazio quando a autenticação está desligada
+	Total     int
+	CreatedAt time.Time
+}
+
+type BatchItem struct {
+	Index        int
+	ID           string
+	ProblemID    string
+	Status       string
+	ErrorMessage string
+}
+
+// BatchStatus é o progresso agregado de um lote: quantas submissões estão em cada status.
+type BatchStatus struct {
+	Batch
+	Counts map[string]int
+	Items  []BatchItem
+}
+
+// Done indica que todas as submissões do lote chegaram a um status final.
+func (b BatchStatus) Done() bool {
+	return b.Counts[StatusSuccess]+b.Counts[StatusError] == b.Total
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+const (
+	CallbackPending   = "pending"
+	CallbackDelivered = "delivered"
+	CallbackFailed    = "failed"
+)
+
+// Tipos de evento enviados nos callbacks.
+const (
+	EventSubmissionProcessing = "submission.processing"
+	EventSubmissionCompleted  = "submission.completed"
+	EventSubmissionFailed     = "submission.failed"
+)
+
+// CallbackEvent é o corpo de todo callback, seja de transição de status ou de resultado final.
+type CallbackEvent struct {
+	Event        string           `json:"event"`
+	SubmissionID string           `json:"submission_id"`
+	Status       string           `json:"status"`
+	Result       *ExecutionReport `json:"result,omitempty"`
+	ErrorMessage string           `json:"error,omitempty"`
+	Timestamp    time.Time        `json:"timestamp"`
+}
+
+type CallbackDelivery struct {
+	ID            int64
+	SubmissionID  string
+	URL           string
+	Payload       string
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	// contexto de trace do job que gerou o callback, em JSON
+	TraceContext string
+}
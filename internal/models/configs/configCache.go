@@ -1,9 +1,21 @@
 package configs
 
+import "time"
+
 type ConfigCache struct {
 	ONLYLOCAL          bool
 	APIURL             string
 	APIKEY             string
 	CACHEDIRECTORY     string
 	CACHEFILEEXTENSION string
+	// tamanho máximo do .zip enviado em POST /problems/{id}
+	MAXPACKAGEBYTES int64
+	// tamanho máximo de cada arquivo do pacote extraído
+	MAXFILEBYTES int64
+	// soma máxima dos arquivos extraídos de um pacote
+	MAXEXTRACTEDBYTES int64
+	// intervalo mínimo entre as revalidações de um pacote com a API; zero desliga
+	REVALIDATEINTERVAL time.Duration
+	// tempo máximo de cada download (ou revalidação) de pacote na API
+	APITIMEOUT time.Duration
 }
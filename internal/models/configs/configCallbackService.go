@@ -0,0 +1,14 @@
+package configs
+
+import "time"
+
+type CallbackServiceConfig struct {
+	CallbackUrl string
+	Secret      string // chave HMAC dos callbacks; vazio desativa a assinatura
+	MaxAttempts int
+	// envia também o evento de transição para processing, além dos estados finais
+	ProcessingEvents bool
+	BaseBackoff      time.Duration
+	MaxBackoff       time.Duration
+	RequestTimeout   time.Duration
+}
@@ -0,0 +1,13 @@
+package configs
+
+import "time"
+
+type HealthServiceConfig struct {
+	CacheDirectory string
+	RunnerPath     string
+	QueueSize      int
+	// percentual de QUEUE_SIZE a partir do qual a instância deixa de ficar pronta
+	QueueSaturationPercent int
+	// tempo máximo de cada verificação
+	CheckTimeout time.Duration
+}
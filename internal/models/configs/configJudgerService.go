@@ -0,0 +1,16 @@
+package configs
+
+import "time"
+
+type JudgerServiceConfig struct {
+	// hosts aceitos em webhook_url; vazio recusa qualquer webhook por submissão
+	AllowedWebhookHosts []string
+
+	// limites padrão das execuções do modo run (POST /run)
+	RunTimeLimit        time.Duration
+	RunMemoryLimitMB    int
+	RunOutputLimitBytes int
+
+	// número máximo de submissões em um POST /submit/batch
+	BatchMaxSize int
+}
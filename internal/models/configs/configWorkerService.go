@@ -4,9 +4,18 @@ import "time"
 
 type WorkerServiceConfig struct {
 	ExecutionDirectory string
-	CallbackUrl        string
 	RunnerPath         string
 	ContainerTimeout   time.Duration
+	LeaseTimeout       time.Duration
+	ReaperInterval     time.Duration
 	MaxWorkers         int
 	QueueSize          int
+	// pesos e workers reservados por lane de prioridade, indexados pelo token da lane
+	LaneWeights         map[string]int
+	LaneReservedWorkers map[string]int
+	// por quanto tempo uma Idempotency-Key repetida devolve a submissão original
+	IdempotencyTTL time.Duration
+	// reservas de um job antes de ele ser encerrado como error, para um job que derruba o worker
+	// (ou perde o lease sempre) não ser retomado para sempre
+	MaxAttempts int
 }
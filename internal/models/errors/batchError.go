@@ -0,0 +1,26 @@
+package customErrors
+
+import "fmt"
+
+// BatchItemError é o erro de validação de uma submissão de um lote, pela posição no array.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+// BatchError reúne os erros de todas as submissões inválidas de um lote. Como o lote é recusado
+// por inteiro, ele se comporta como ErrValidation em errors.Is.
+type BatchError struct {
+	Items []BatchItemError
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Items) == 1 {
+		return fmt.Sprintf("submission %d is invalid: %v", e.Items[0].Index, e.Items[0].Err)
+	}
+	return fmt.Sprintf("%d submissions are invalid", len(e.Items))
+}
+
+func (e *BatchError) Unwrap() error {
+	return ErrValidation
+}
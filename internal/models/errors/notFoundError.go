@@ -3,5 +3,28 @@ package customErrors
 import "errors"
 
 var (
-	ErrNotFound = errors.New("not found")
+	ErrNotFound     = errors.New("not found")
+	ErrQueueFull    = errors.New("queue is full")
+	ErrShuttingDown = errors.New("server is shutting down")
+
+	ErrValidation        = errors.New("validation failed")
+	ErrInvalidLanguage   = errors.New("invalid language")
+	ErrProblemNotFound   = errors.New("problem not found")
+	ErrInvalidWebhookURL = errors.New("invalid webhook_url")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrInvalidFilter     = errors.New("invalid filter")
+
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrQuotaExceeded = errors.New("client quota exceeded")
+	ErrRateLimited   = errors.New("rate limit exceeded")
+
+	ErrIdempotencyConflict     = errors.New("idempotency key already used with a different request")
+	ErrDuplicateIdempotencyKey = errors.New("idempotency key already used")
+
+	ErrNotCancelable = errors.New("submission is no longer queued")
+
+	ErrCallbackSuperseded = errors.New("the submission's final event was already delivered")
+
+	ErrInvalidPackage  = errors.New("invalid problem package")
+	ErrPackageTooLarge = errors.New("problem package too large")
 )
@@ -0,0 +1,23 @@
+package customErrors
+
+import (
+	"IFJudger/internal/models"
+	"fmt"
+)
+
+// PackageError reúne os erros encontrados na validação de um pacote de problema. Ele se comporta
+// como ErrInvalidPackage em errors.Is.
+type PackageError struct {
+	Issues []models.PackageIssue
+}
+
+func (e *PackageError) Error() string {
+	if len(e.Issues) == 1 {
+		return fmt.Sprintf("%v: %s", ErrInvalidPackage, e.Issues[0].Message)
+	}
+	return fmt.Sprintf("%v: %d issues found", ErrInvalidPackage, len(e.Issues))
+}
+
+func (e *PackageError) Unwrap() error {
+	return ErrInvalidPackage
+}
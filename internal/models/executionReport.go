@@ -2,6 +2,34 @@ package models
 
 type ExecutionReport struct {
 	Results []TestCaseResult `json:"results"`
+	Run     *RunResult       `json:"run,omitempty"` // preenchido apenas para jobs do modo run
+}
+
+// Verdict resume o relatório em um veredicto: o status do primeiro caso que não passou,
+// ou AC quando todos passaram. Jobs do modo run usam o status da execução.
+func (r ExecutionReport) Verdict() string {
+	if r.Run != nil {
+		return r.Run.Status
+	}
+	if len(r.Results) == 0 {
+		return "IER"
+	}
+	for _, result := range r.Results {
+		if result.Status != "AC" {
+			return result.Status
+		}
+	}
+	return "AC"
+}
+
+type RunResult struct {
+	Status    string `json:"status"` // OK, TLE, RTE
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	Truncated bool   `json:"truncated,omitempty"`
+	ExitCode  int    `json:"exit_code"`
+	TimeMS    int64  `json:"time_ms"`
+	MemoryKB  int64  `json:"memory_kb"`
 }
 
 type TestCaseResult struct {
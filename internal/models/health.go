@@ -0,0 +1,19 @@
+package models
+
+// Estados de um componente verificado em GET /readyz.
+const (
+	HealthOK   = "ok"
+	HealthFail = "fail"
+)
+
+type ComponentHealth struct {
+	Name      string
+	Status    string
+	Message   string
+	LatencyMS int64
+}
+
+type ReadinessReport struct {
+	Ready      bool
+	Components []ComponentHealth
+}
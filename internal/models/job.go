@@ -4,14 +4,36 @@ import (
 	"time"
 )
 
+const (
+	JobKindJudge = "judge"
+	JobKindRun   = "run"
+)
+
 type Job struct {
 	ID           string
+	ProblemID    string
+	ClientID     string // chave de API que criou o job; vazio quando a autenticação está desligada
 	LanguageID   LanguageID
-	CachePath    string
+	CachePath    string // versão do pacote resolvida no enfileiramento, não o symlink do cache
 	TimeLimit    time.Duration
 	MaximumRamMB int
 	Code         string
 	WebhookURL   string
+	Priority     Priority
+	// Kind vazio equivale a JobKindJudge (jobs criados antes do modo run)
+	Kind             string
+	Input            string
+	OutputLimitBytes int
+	// momento em que o job entrou na fila, usado para medir a espera até um worker reservá-lo
+	EnqueuedAt time.Time
+	// contexto de trace da requisição que criou o job, para continuar o trace no worker
+	TraceContext map[string]string
+	// Idempotency-Key enviada pelo cliente e a impressão digital da requisição, para detectar
+	// a mesma chave reutilizada com outro conteúdo
+	IdempotencyKey  string
+	IdempotencyHash string
+	// quantas vezes o job já foi reservado, contando a reserva atual; vem da coluna attempts
+	Attempts int `json:"-"`
 }
 
 type JobResult struct {
@@ -19,4 +41,6 @@ type JobResult struct {
 	Status       string
 	Result       ExecutionReport
 	ErrorMessage string
+	// Kind do job; vazio equivale a JobKindJudge
+	Kind string
 }
@@ -1,16 +1,80 @@
 package models
 
+import "strings"
+
 type LanguageID int
 
 const (
 	Python LanguageID = 1
 )
 
+// Language descreve uma linguagem aceita nas submissões e como ela é executada no container.
+// Languages é a única fonte dessas informações: validação, execução, readiness e GET /languages leem daqui.
+type Language struct {
+	ID            LanguageID
+	Token         string // identificador usado na API (campo language do /submit)
+	Name          string
+	Version       string
+	Image         string // imagem Docker do container de execução
+	FileExtension string
+	// comandos executados dentro do container, a partir do diretório com o código;
+	// CompileCommand fica vazio nas linguagens interpretadas
+	CompileCommand []string
+	RunCommand     []string
+}
+
+// Languages são as linguagens aceitas nas submissões.
+var Languages = []Language{
+	{
+		ID:            Python,
+		Token:         "python",
+		Name:          "Python",
+		Version:       "3.12",
+		Image:         "python:3.12.12-slim",
+		FileExtension: ".py",
+		RunCommand:    []string{"python", "source.py"},
+	},
+}
+
+// SourceFile é o nome do arquivo com o código da submissão dentro do container.
+func (l Language) SourceFile() string {
+	return "source" + l.FileExtension
+}
+
+// LanguageByToken busca a linguagem pelo identificador usado na API.
+func LanguageByToken(token string) (Language, bool) {
+	for _, language := range Languages {
+		if language.Token == token {
+			return language, true
+		}
+	}
+	return Language{}, false
+}
+
+// Language devolve a descrição da linguagem; ok é false para ids desconhecidos.
+func (l LanguageID) Language() (Language, bool) {
+	for _, language := range Languages {
+		if language.ID == l {
+			return language, true
+		}
+	}
+	return Language{}, false
+}
+
+// Token é o identificador da linguagem usado na API (campo language do /submit).
+func (l LanguageID) Token() string {
+	language, _ := l.Language()
+	return language.Token
+}
+
 func (l LanguageID) String() string {
-	switch l {
-	case Python:
-		return "Python"
-	default:
-		return "Unknown"
+	if language, ok := l.Language(); ok {
+		return language.Name
 	}
+	return "Unknown"
+}
+
+// FormatCommand junta os argumentos de um comando para exibição, ex.: "python source.py".
+func FormatCommand(command []string) string {
+	return strings.Join(command, " ")
 }
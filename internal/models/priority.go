@@ -0,0 +1,32 @@
+package models
+
+import "fmt"
+
+type Priority string
+
+const (
+	PriorityContest  Priority = "contest"
+	PriorityPractice Priority = "practice"
+	PriorityRejudge  Priority = "rejudge"
+	// PriorityRun é a lane exclusiva das execuções com entrada do usuário (POST /run)
+	PriorityRun Priority = "run"
+)
+
+// Priorities lista as lanes da fila, da mais urgente para a menos urgente.
+var Priorities = []Priority{PriorityContest, PriorityPractice, PriorityRun, PriorityRejudge}
+
+const DefaultPriority = PriorityPractice
+
+func ParsePriority(token string) (Priority, error) {
+	if token == "" {
+		return DefaultPriority, nil
+	}
+
+	for _, p := range Priorities {
+		if string(p) == token {
+			return p, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid priority %q", token)
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// ProblemPackage descreve uma versão instalada do pacote de um problema no cache.
+type ProblemPackage struct {
+	ProblemID   string
+	Version     string
+	SHA256      string // hash do .zip que originou a versão
+	TestCases   int
+	Languages   []LanguageLimits
+	InstalledAt time.Time
+	// avisos da validação, que não impediram a instalação
+	Warnings []PackageIssue
+}
+
+// Gravidade de um problema encontrado na validação do pacote. Erros impedem a instalação.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Códigos dos problemas encontrados na validação do pacote.
+const (
+	IssueInvalidMeta       = "invalid_meta"
+	IssueNoLanguages       = "no_languages"
+	IssueDuplicateLanguage = "duplicate_language"
+	IssueInvalidLimit      = "invalid_limit"
+	IssueUnknownLanguage   = "unknown_language"
+	IssueOrphanFile        = "orphan_file"
+	IssueNoTestCases       = "no_test_cases"
+	IssueMixedLineEndings  = "mixed_line_endings"
+	IssueFileTooLarge      = "file_too_large"
+)
+
+// PackageIssue é um problema encontrado na validação do pacote; File é relativo à raiz do pacote.
+type PackageIssue struct {
+	Severity string
+	Code     string
+	File     string
+	Message  string
+}
+
+// PackageReport é o resultado da validação de um pacote extraído.
+type PackageReport struct {
+	TestCases int
+	Languages []LanguageLimits
+	Issues    []PackageIssue
+}
+
+func (r PackageReport) Errors() []PackageIssue {
+	return r.filter(SeverityError)
+}
+
+func (r PackageReport) Warnings() []PackageIssue {
+	return r.filter(SeverityWarning)
+}
+
+func (r PackageReport) filter(severity string) []PackageIssue {
+	var issues []PackageIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
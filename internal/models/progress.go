@@ -0,0 +1,48 @@
+package models
+
+// Tipos de evento de progresso de uma submissão, enviados em GET /job/{id}/events.
+const (
+	ProgressQueued     = "queued"
+	ProgressProcessing = "processing"
+	ProgressTestCase   = "test_case"
+	ProgressCompleted  = "completed"
+	ProgressFailed     = "failed"
+)
+
+type ProgressEvent struct {
+	Type         string           `json:"type"`
+	SubmissionID string           `json:"submission_id"`
+	Status       string           `json:"status"`
+	TestCase     *TestCaseResult  `json:"test_case,omitempty"`
+	Result       *ExecutionReport `json:"result,omitempty"`
+	ErrorMessage string           `json:"error,omitempty"`
+}
+
+// ProgressFromResult converte o estado salvo de uma submissão no evento correspondente,
+// usado como primeiro evento para quem começa a acompanhar a submissão.
+func ProgressFromResult(result JobResult) ProgressEvent {
+	event := ProgressEvent{
+		SubmissionID: result.ID,
+		Status:       result.Status,
+	}
+
+	switch result.Status {
+	case StatusQueued:
+		event.Type = ProgressQueued
+	case StatusProcessing:
+		event.Type = ProgressProcessing
+	case StatusSuccess:
+		event.Type = ProgressCompleted
+		event.Result = &result.Result
+	default:
+		event.Type = ProgressFailed
+		event.ErrorMessage = result.ErrorMessage
+	}
+
+	return event
+}
+
+// IsFinal indica se o evento encerra o acompanhamento da submissão.
+func (e ProgressEvent) IsFinal() bool {
+	return e.Type == ProgressCompleted || e.Type == ProgressFailed
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// SubmissionFilter descreve uma busca paginada em submissions.
+// Campos vazios não filtram; Cursor é o NextCursor devolvido pela página anterior.
+type SubmissionFilter struct {
+	Status     string
+	ProblemID  string
+	Language   string
+	Priority   string
+	From       time.Time
+	To         time.Time
+	Descending bool
+	Cursor     string
+	Limit      int
+}
+
+type SubmissionSummary struct {
+	ID           string
+	ProblemID    string
+	Language     string
+	Priority     string
+	Status       string
+	ErrorMessage string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type SubmissionPage struct {
+	Submissions []SubmissionSummary
+	NextCursor  string
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIKeyRepository guarda as chaves de API aceitas pela API. Apenas o hash da chave é salvo.
+type APIKeyRepository struct {
+	DB *sql.DB
+}
+
+func StartAPIKeyRepository(db *sql.DB) (*APIKeyRepository, error) {
+	createTableSQL := `CREATE TABLE IF NOT EXISTS api_keys (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		key_hash TEXT NOT NULL UNIQUE,
+		scopes TEXT NOT NULL,
+		rate_limit_per_minute INTEGER NOT NULL DEFAULT 0,
+		max_active_jobs INTEGER NOT NULL DEFAULT 0,
+		created_at INTEGER NOT NULL,
+		revoked_at INTEGER
+	);`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return nil, fmt.Errorf("falha ao criar tabela api_keys: %w", err)
+	}
+
+	return &APIKeyRepository{
+		DB: db,
+	}, nil
+}
+
+const apiKeyColumns = `id, name, scopes, rate_limit_per_minute, max_active_jobs, created_at, revoked_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var key models.APIKey
+	var scopes string
+	var createdAt int64
+	var revokedAt sql.NullInt64
+
+	if err := row.Scan(&key.ID, &key.Name, &scopes, &key.RateLimitPerMinute, &key.MaxActiveJobs, &createdAt, &revokedAt); err != nil {
+		return models.APIKey{}, err
+	}
+
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, ",")
+	}
+	key.CreatedAt = time.UnixMilli(createdAt)
+	if revokedAt.Valid {
+		revoked := time.UnixMilli(revokedAt.Int64)
+		key.RevokedAt = &revoked
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepository) Create(key models.APIKey, keyHash string) error {
+	query := `INSERT INTO api_keys (id, name, key_hash, scopes, rate_limit_per_minute, max_active_jobs, created_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := execWithRetry(r.DB, query, key.ID, key.Name, keyHash, strings.Join(key.Scopes, ","),
+		key.RateLimitPerMinute, key.MaxActiveJobs, key.CreatedAt.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("falha ao criar chave de API: %w", err)
+	}
+	return nil
+}
+
+// FindActiveByHash busca uma chave não revogada pelo hash. Retorna customErrors.ErrNotFound se não existir.
+func (r *APIKeyRepository) FindActiveByHash(keyHash string) (models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL`
+
+	key, err := scanAPIKey(r.DB.QueryRow(query, keyHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.APIKey{}, customErrors.ErrNotFound
+		}
+		return models.APIKey{}, err
+	}
+	return key, nil
+}
+
+// ExistsByHash indica se alguma chave, mesmo revogada, tem esse hash.
+func (r *APIKeyRepository) ExistsByHash(keyHash string) (bool, error) {
+	var count int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM api_keys WHERE key_hash = ?`, keyHash).Scan(&count)
+	return count > 0, err
+}
+
+func (r *APIKeyRepository) List() ([]models.APIKey, error) {
+	rows, err := r.DB.Query(`SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *APIKeyRepository) Revoke(id string) error {
+	res, err := execWithRetry(r.DB, `UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`, time.Now().UnixMilli(), id)
+	if err != nil {
+		return fmt.Errorf("falha ao revogar chave de API: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("falha ao revogar chave de API: %w", err)
+	}
+	if affected == 0 {
+		return customErrors.ErrNotFound
+	}
+	return nil
+}
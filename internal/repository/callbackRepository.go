@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CallbackRepository guarda o outbox de callbacks: cada resultado a ser enviado ao backend
+// é gravado aqui antes da entrega, para sobreviver a falhas da API e a reinícios do judger.
+type CallbackRepository struct {
+	DB *sql.DB
+}
+
+func StartCallbackRepository(db *sql.DB) (*CallbackRepository, error) {
+	createTableSQL := `CREATE TABLE IF NOT EXISTS callback_outbox (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		submission_id TEXT NOT NULL,
+		url TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at INTEGER NOT NULL,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL,
+		trace_context TEXT NOT NULL DEFAULT ''
+	);`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return nil, fmt.Errorf("falha ao criar tabela callback_outbox: %w", err)
+	}
+
+	if err := ensureColumn(db, "callback_outbox", "trace_context", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_callback_outbox_due ON callback_outbox (status, next_attempt_at);`); err != nil {
+		return nil, fmt.Errorf("falha ao criar índice do outbox: %w", err)
+	}
+
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_callback_outbox_submission ON callback_outbox (submission_id, status, id);`); err != nil {
+		return nil, fmt.Errorf("falha ao criar índice do outbox: %w", err)
+	}
+
+	return &CallbackRepository{
+		DB: db,
+	}, nil
+}
+
+const callbackColumns = `id, submission_id, url, payload, status, attempts, next_attempt_at, last_error, created_at, updated_at, trace_context`
+
+func scanCallback(rows *sql.Rows) (models.CallbackDelivery, error) {
+	var d models.CallbackDelivery
+	var nextAttemptAt, createdAt, updatedAt int64
+
+	err := rows.Scan(&d.ID, &d.SubmissionID, &d.URL, &d.Payload, &d.Status, &d.Attempts, &nextAttemptAt, &d.LastError, &createdAt, &updatedAt, &d.TraceContext)
+	if err != nil {
+		return models.CallbackDelivery{}, err
+	}
+
+	d.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+	d.CreatedAt = time.UnixMilli(createdAt)
+	d.UpdatedAt = time.UnixMilli(updatedAt)
+	return d, nil
+}
+
+// Enqueue grava um callback pendente. traceContext é o contexto de trace serializado do job,
+// para que a entrega apareça no mesmo trace da submissão.
+func (r *CallbackRepository) Enqueue(submissionID, url, payload, traceContext string) error {
+	now := time.Now().UnixMilli()
+	query := `INSERT INTO callback_outbox (submission_id, url, payload, status, attempts, next_attempt_at, created_at, updated_at, trace_context)
+              VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?)`
+
+	if _, err := execWithRetry(r.DB, query, submissionID, url, payload, models.CallbackPending, now, now, now, traceContext); err != nil {
+		return fmt.Errorf("falha ao gravar callback no outbox: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue reserva até limit callbacks pendentes cujo horário de tentativa já chegou.
+// O próximo horário é empurrado em lease para que outra instância não entregue o mesmo callback em paralelo.
+// Só o callback pendente mais antigo de cada submissão é reservado: um processing reenviado
+// nunca chega depois do completed da mesma submissão.
+func (r *CallbackRepository) ClaimDue(limit int, lease time.Duration) ([]models.CallbackDelivery, error) {
+	now := time.Now()
+	query := `UPDATE callback_outbox
+              SET next_attempt_at = ?, updated_at = ?
+              WHERE id IN (
+                  SELECT id FROM callback_outbox AS c
+                  WHERE status = ? AND next_attempt_at <= ?
+                    AND NOT EXISTS (
+                        SELECT 1 FROM callback_outbox AS older
+                        WHERE older.submission_id = c.submission_id AND older.status = c.status AND older.id < c.id
+                    )
+                  ORDER BY next_attempt_at
+                  LIMIT ?
+              )
+              RETURNING ` + callbackColumns
+
+	rows, err := r.DB.Query(query, now.Add(lease).UnixMilli(), now.UnixMilli(), models.CallbackPending, now.UnixMilli(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao reservar callbacks: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.CallbackDelivery
+	for rows.Next() {
+		d, err := scanCallback(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *CallbackRepository) MarkDelivered(id int64, attempts int) error {
+	query := `UPDATE callback_outbox SET status = ?, attempts = ?, last_error = '', updated_at = ? WHERE id = ?`
+
+	if _, err := execWithRetry(r.DB, query, models.CallbackDelivered, attempts, time.Now().UnixMilli(), id); err != nil {
+		return fmt.Errorf("falha ao marcar callback como entregue: %w", err)
+	}
+	return nil
+}
+
+// MarkRetry registra uma tentativa falha e agenda a próxima.
+func (r *CallbackRepository) MarkRetry(id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	query := `UPDATE callback_outbox SET attempts = ?, next_attempt_at = ?, last_error = ?, updated_at = ? WHERE id = ?`
+
+	if _, err := execWithRetry(r.DB, query, attempts, nextAttemptAt.UnixMilli(), lastError, time.Now().UnixMilli(), id); err != nil {
+		return fmt.Errorf("falha ao reagendar callback: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed encerra as tentativas de um callback; ele só volta a ser enviado via Retrigger.
+func (r *CallbackRepository) MarkFailed(id int64, attempts int, lastError string) error {
+	query := `UPDATE callback_outbox SET status = ?, attempts = ?, last_error = ?, updated_at = ? WHERE id = ?`
+
+	if _, err := execWithRetry(r.DB, query, models.CallbackFailed, attempts, lastError, time.Now().UnixMilli(), id); err != nil {
+		return fmt.Errorf("falha ao marcar callback como falho: %w", err)
+	}
+	return nil
+}
+
+// Retrigger devolve um callback para pending com as tentativas zeradas, para entrega imediata.
+// Retorna customErrors.ErrNotFound se o callback não existir ou já tiver sido entregue, e
+// customErrors.ErrCallbackSuperseded para um submission.processing cuja submissão já teve o evento
+// final entregue: ele chegaria por último e faria o receptor voltar a submissão para processing.
+func (r *CallbackRepository) Retrigger(id int64) error {
+	now := time.Now().UnixMilli()
+	query := `UPDATE callback_outbox AS c
+              SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ?
+              WHERE id = ? AND status != ?
+                AND NOT (json_extract(payload, '$.event') = ? AND EXISTS (
+                    SELECT 1 FROM callback_outbox AS final
+                    WHERE final.submission_id = c.submission_id AND final.status = ?
+                      AND json_extract(final.payload, '$.event') != ?
+                ))`
+
+	res, err := execWithRetry(r.DB, query, models.CallbackPending, now, now, id, models.CallbackDelivered,
+		models.EventSubmissionProcessing, models.CallbackDelivered, models.EventSubmissionProcessing)
+	if err != nil {
+		return fmt.Errorf("falha ao reenviar callback: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("falha ao reenviar callback: %w", err)
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	var status string
+	err = r.DB.QueryRow(`SELECT status FROM callback_outbox WHERE id = ?`, id).Scan(&status)
+	if err == sql.ErrNoRows || (err == nil && status == models.CallbackDelivered) {
+		return customErrors.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("falha ao buscar callback: %w", err)
+	}
+	return customErrors.ErrCallbackSuperseded
+}
+
+// List retorna os callbacks mais recentes com o status informado (todos, se status for vazio).
+func (r *CallbackRepository) List(status string, limit int) ([]models.CallbackDelivery, error) {
+	query := `SELECT ` + callbackColumns + ` FROM callback_outbox
+              WHERE (? = '' OR status = ?)
+              ORDER BY id DESC
+              LIMIT ?`
+
+	rows, err := r.DB.Query(query, status, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.CallbackDelivery{}
+	for rows.Next() {
+		d, err := scanCallback(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,79 @@
+package repository_test
+
+import (
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/internal/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newCallbackRepository(t *testing.T) *repository.CallbackRepository {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "judger.db"))
+	if err != nil {
+		t.Fatalf("abrir banco: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo, err := repository.StartCallbackRepository(db)
+	if err != nil {
+		t.Fatalf("StartCallbackRepository: %v", err)
+	}
+	return repo
+}
+
+func enqueueEvent(t *testing.T, repo *repository.CallbackRepository, submissionID, event string) {
+	t.Helper()
+	payload, _ := json.Marshal(models.CallbackEvent{Event: event, SubmissionID: submissionID, Timestamp: time.Now()})
+	if err := repo.Enqueue(submissionID, "http://receptor/callback", string(payload), ""); err != nil {
+		t.Fatalf("Enqueue(%s): %v", event, err)
+	}
+}
+
+func TestRetriggerSkipsSupersededProcessing(t *testing.T) {
+	repo := newCallbackRepository(t)
+	enqueueEvent(t, repo, "sub-1", models.EventSubmissionProcessing)
+	enqueueEvent(t, repo, "sub-1", models.EventSubmissionCompleted)
+	enqueueEvent(t, repo, "sub-2", models.EventSubmissionProcessing)
+	// os ids do outbox seguem a ordem de gravação
+	const processing, completed, otherProcessing = 1, 2, 3
+
+	// o processing esgotou as tentativas e o completed foi entregue depois
+	if err := repo.MarkFailed(processing, 5, "timeout"); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+	if err := repo.MarkDelivered(completed, 1); err != nil {
+		t.Fatalf("MarkDelivered: %v", err)
+	}
+	if err := repo.MarkFailed(otherProcessing, 5, "timeout"); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+
+	if err := repo.Retrigger(processing); !errors.Is(err, customErrors.ErrCallbackSuperseded) {
+		t.Errorf("Retrigger do processing superado: err = %v, esperado ErrCallbackSuperseded", err)
+	}
+	if err := repo.Retrigger(completed); !errors.Is(err, customErrors.ErrNotFound) {
+		t.Errorf("Retrigger de callback entregue: err = %v, esperado ErrNotFound", err)
+	}
+	if err := repo.Retrigger(99); !errors.Is(err, customErrors.ErrNotFound) {
+		t.Errorf("Retrigger de callback inexistente: err = %v, esperado ErrNotFound", err)
+	}
+
+	// sem evento final entregue, o processing pode ser reenviado
+	if err := repo.Retrigger(otherProcessing); err != nil {
+		t.Fatalf("Retrigger: %v", err)
+	}
+	due, err := repo.ClaimDue(10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != otherProcessing {
+		t.Errorf("callbacks reservados = %+v, esperado só o %d", due, otherProcessing)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"IFJudger/internal/models"
 	customErrors "IFJudger/internal/models/errors"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -21,13 +23,69 @@ func StartSubmissionRepository(db *sql.DB) (*SubmissionRepository, error) {
 		result_json TEXT,
 		error_message TEXT,
 		job_data TEXT, 
-		updated_at DATETIME
+		updated_at DATETIME,
+		lease_owner TEXT,
+		lease_expires_at INTEGER,
+		priority TEXT NOT NULL DEFAULT 'practice',
+		problem_id TEXT,
+		language TEXT,
+		created_at INTEGER,
+		api_key_id TEXT,
+		idempotency_key TEXT,
+		idempotency_hash TEXT,
+		batch_id TEXT,
+		batch_index INTEGER,
+		attempts INTEGER NOT NULL DEFAULT 0
 	);`
 
 	if _, err := db.Exec(createTableSQL); err != nil {
 		return nil, fmt.Errorf("falha ao criar tabela submissions: %w", err)
 	}
 
+	// bancos criados antes da fila persistente não possuem as colunas de lease
+	if err := ensureColumn(db, "submissions", "lease_owner", "TEXT"); err != nil {
+		return nil, err
+	}
+	if err := ensureColumn(db, "submissions", "lease_expires_at", "INTEGER"); err != nil {
+		return nil, err
+	}
+	if err := ensureColumn(db, "submissions", "priority", "TEXT NOT NULL DEFAULT 'practice'"); err != nil {
+		return nil, err
+	}
+	for _, column := range []string{"problem_id TEXT", "language TEXT", "created_at INTEGER", "api_key_id TEXT", "idempotency_key TEXT", "idempotency_hash TEXT", "batch_id TEXT", "batch_index INTEGER", "attempts INTEGER NOT NULL DEFAULT 0"} {
+		name, definition, _ := strings.Cut(column, " ")
+		if err := ensureColumn(db, "submissions", name, definition); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_submissions_status ON submissions (status, priority);`); err != nil {
+		return nil, fmt.Errorf("falha ao criar índice de status: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_submissions_created_at ON submissions (created_at);`); err != nil {
+		return nil, fmt.Errorf("falha ao criar índice de created_at: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_submissions_api_key ON submissions (api_key_id, status);`); err != nil {
+		return nil, fmt.Errorf("falha ao criar índice de api_key_id: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_submissions_idempotency ON submissions (idempotency_key, created_at) WHERE idempotency_key IS NOT NULL;`); err != nil {
+		return nil, fmt.Errorf("falha ao criar índice de idempotency_key: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_submissions_batch ON submissions (batch_id, batch_index) WHERE batch_id IS NOT NULL;`); err != nil {
+		return nil, fmt.Errorf("falha ao criar índice de batch_id: %w", err)
+	}
+
+	// lotes de POST /submit/batch; as submissões apontam para o lote em submissions.batch_id
+	createBatchesSQL := `CREATE TABLE IF NOT EXISTS batches (
+		id TEXT PRIMARY KEY,
+		api_key_id TEXT,
+		total INTEGER NOT NULL,
+		created_at INTEGER NOT NULL
+	);`
+	if _, err := db.Exec(createBatchesSQL); err != nil {
+		return nil, fmt.Errorf("falha ao criar tabela batches: %w", err)
+	}
+
 	_, _ = db.Exec("PRAGMA journal_mode=WAL;")
 	_, _ = db.Exec("PRAGMA synchronous = NORMAL;")
 
@@ -36,70 +94,542 @@ func StartSubmissionRepository(db *sql.DB) (*SubmissionRepository, error) {
 	}, nil
 }
 
-func (r *SubmissionRepository) execWithRetry(query string, args ...interface{}) error {
+func ensureColumn(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s);", table))
+	if err != nil {
+		return fmt.Errorf("falha ao ler colunas de %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table, column, definition)); err != nil {
+		return fmt.Errorf("falha ao adicionar coluna %s em %s: %w", column, table, err)
+	}
+	return nil
+}
+
+func isBusyError(err error) bool {
+	errMsg := strings.ToLower(err.Error())
+	return strings.Contains(errMsg, "locked") || strings.Contains(errMsg, "busy")
+}
+
+func execWithRetry(db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
 	const maxRetries = 20
 	const baseDelay = 100 * time.Millisecond
 
 	var err error
 	for i := 0; i < maxRetries; i++ {
-		_, err = r.DB.Exec(query, args...)
+		var res sql.Result
+		res, err = db.Exec(query, args...)
 		if err == nil {
-			return nil
+			return res, nil
 		}
 
-		errMsg := strings.ToLower(err.Error())
-		if strings.Contains(errMsg, "locked") || strings.Contains(errMsg, "busy") {
+		if isBusyError(err) {
 			time.Sleep(baseDelay * time.Duration(i+1))
 			continue
 		}
 
-		return err
+		return nil, err
 	}
 
-	return fmt.Errorf("falha após %d tentativas (banco travado): %w", maxRetries, err)
+	return nil, fmt.Errorf("falha após %d tentativas (banco travado): %w", maxRetries, err)
 }
 
-func (r *SubmissionRepository) CreateJob(job models.Job) error {
+// CreateJob insere o job como queued, desde que a fila não tenha atingido maxQueued
+// e, se clientQuota > 0, o cliente (job.ClientID) tenha menos de clientQuota jobs ativos.
+// Com job.IdempotencyKey, o job também só é inserido se o cliente não tiver usado a mesma chave
+// desde idempotencySince; caso contrário retorna customErrors.ErrDuplicateIdempotencyKey.
+// As contagens e a inserção acontecem no mesmo statement para não haver corrida entre requisições.
+func (r *SubmissionRepository) CreateJob(job models.Job, maxQueued int, clientQuota int, idempotencySince time.Time) error {
 	jobDataJSON, err := json.Marshal(job)
 	if err != nil {
 		return fmt.Errorf("falha ao serializar job data: %w", err)
 	}
 
-	query := `INSERT INTO submissions (id, status, result_json, error_message, job_data, updated_at) 
-              VALUES (?, ?, ?, ?, ?, ?)`
+	priority := job.Priority
+	if priority == "" {
+		priority = models.DefaultPriority
+	}
+
+	now := time.Now()
+	query := `INSERT INTO submissions (id, status, result_json, error_message, job_data, updated_at, priority, problem_id, language, created_at, api_key_id, idempotency_key, idempotency_hash) 
+              SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+              WHERE (SELECT COUNT(*) FROM submissions WHERE status = ?) < ?
+                AND (? <= 0 OR (SELECT COUNT(*) FROM submissions WHERE api_key_id = ? AND status IN (?, ?)) < ?)
+                AND (? = '' OR NOT EXISTS (SELECT 1 FROM submissions WHERE idempotency_key = ? AND COALESCE(api_key_id, '') = ? AND created_at >= ?))`
+
+	res, err := execWithRetry(r.DB, query,
+		job.ID, models.StatusQueued, "", "", string(jobDataJSON), now, string(priority), job.ProblemID, job.LanguageID.Token(), now.UnixMilli(), nullIfEmpty(job.ClientID), nullIfEmpty(job.IdempotencyKey), nullIfEmpty(job.IdempotencyHash),
+		models.StatusQueued, maxQueued,
+		clientQuota, job.ClientID, models.StatusQueued, models.StatusProcessing, clientQuota,
+		job.IdempotencyKey, job.IdempotencyKey, job.ClientID, idempotencySince.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("falha ao criar job inicial: %w", err)
+	}
 
-	if err := r.execWithRetry(query, job.ID, models.StatusQueued, "", "", string(jobDataJSON), time.Now()); err != nil {
+	affected, err := res.RowsAffected()
+	if err != nil {
 		return fmt.Errorf("falha ao criar job inicial: %w", err)
 	}
+	if affected == 0 {
+		if job.IdempotencyKey != "" {
+			if _, _, err := r.FindByIdempotencyKey(job.ClientID, job.IdempotencyKey, idempotencySince); err == nil {
+				return customErrors.ErrDuplicateIdempotencyKey
+			}
+		}
+		if clientQuota > 0 {
+			active, err := r.CountActiveByClient(job.ClientID)
+			if err == nil && active >= clientQuota {
+				return customErrors.ErrQuotaExceeded
+			}
+		}
+		return customErrors.ErrQueueFull
+	}
 
 	return nil
 }
 
-func (r *SubmissionRepository) UpdateResult(result models.JobResult) error {
+// CreateBatch grava o lote e todas as suas submissões como queued em uma única transação:
+// ou todas entram na fila, ou nenhuma. As mesmas regras do CreateJob valem para o lote inteiro,
+// a fila precisa comportar todas as submissões e o cliente não pode passar de clientQuota jobs ativos.
+func (r *SubmissionRepository) CreateBatch(batch models.Batch, jobs []models.Job, maxQueued int, clientQuota int) error {
+	return withTransaction(r.DB, func(tx *sql.Tx) error {
+		now := time.Now()
+
+		// a inserção do lote vem primeiro para a transação já reservar a escrita no SQLite,
+		// assim as contagens abaixo não mudam até o commit
+		if _, err := tx.Exec(`INSERT INTO batches (id, api_key_id, total, created_at) VALUES (?, ?, ?, ?)`,
+			batch.ID, nullIfEmpty(batch.ClientID), len(jobs), now.UnixMilli()); err != nil {
+			return fmt.Errorf("falha ao criar lote: %w", err)
+		}
+
+		var queued int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM submissions WHERE status = ?`, models.StatusQueued).Scan(&queued); err != nil {
+			return fmt.Errorf("falha ao contar jobs na fila: %w", err)
+		}
+		if queued+len(jobs) > maxQueued {
+			return customErrors.ErrQueueFull
+		}
+
+		if clientQuota > 0 {
+			var active int
+			err := tx.QueryRow(`SELECT COUNT(*) FROM submissions WHERE api_key_id = ? AND status IN (?, ?)`,
+				batch.ClientID, models.StatusQueued, models.StatusProcessing).Scan(&active)
+			if err != nil {
+				return fmt.Errorf("falha ao contar jobs ativos do cliente: %w", err)
+			}
+			if active+len(jobs) > clientQuota {
+				return customErrors.ErrQuotaExceeded
+			}
+		}
+
+		stmt, err := tx.Prepare(`INSERT INTO submissions (id, status, result_json, error_message, job_data, updated_at, priority, problem_id, language, created_at, api_key_id, batch_id, batch_index)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return fmt.Errorf("falha ao preparar inserção do lote: %w", err)
+		}
+		defer stmt.Close()
+
+		for index, job := range jobs {
+			jobDataJSON, err := json.Marshal(job)
+			if err != nil {
+				return fmt.Errorf("falha ao serializar job data: %w", err)
+			}
+
+			priority := job.Priority
+			if priority == "" {
+				priority = models.DefaultPriority
+			}
+
+			_, err = stmt.Exec(job.ID, models.StatusQueued, "", "", string(jobDataJSON), now, string(priority), job.ProblemID, job.LanguageID.Token(), now.UnixMilli(), nullIfEmpty(job.ClientID), batch.ID, index)
+			if err != nil {
+				return fmt.Errorf("falha ao criar job %d do lote: %w", index, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetBatch devolve o lote com o status de cada submissão, na ordem em que foram enviadas.
+// Retorna customErrors.ErrNotFound se o lote não existir.
+func (r *SubmissionRepository) GetBatch(id string) (models.BatchStatus, error) {
+	var status models.BatchStatus
+	var clientID sql.NullString
+	var createdAt int64
+
+	err := r.DB.QueryRow(`SELECT id, api_key_id, total, created_at FROM batches WHERE id = ?`, id).
+		Scan(&status.ID, &clientID, &status.Total, &createdAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.BatchStatus{}, customErrors.ErrNotFound
+		}
+		return models.BatchStatus{}, fmt.Errorf("falha ao buscar lote: %w", err)
+	}
+	status.ClientID = clientID.String
+	status.CreatedAt = time.UnixMilli(createdAt)
+
+	rows, err := r.DB.Query(`SELECT batch_index, id, COALESCE(problem_id, ''), status, COALESCE(error_message, '')
+              FROM submissions WHERE batch_id = ? ORDER BY batch_index`, id)
+	if err != nil {
+		return models.BatchStatus{}, fmt.Errorf("falha ao buscar submissões do lote: %w", err)
+	}
+	defer rows.Close()
+
+	status.Counts = make(map[string]int)
+	for rows.Next() {
+		var item models.BatchItem
+		if err := rows.Scan(&item.Index, &item.ID, &item.ProblemID, &item.Status, &item.ErrorMessage); err != nil {
+			return models.BatchStatus{}, fmt.Errorf("falha ao ler submissão do lote: %w", err)
+		}
+		status.Counts[item.Status]++
+		status.Items = append(status.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return models.BatchStatus{}, fmt.Errorf("falha ao ler submissões do lote: %w", err)
+	}
+
+	return status, nil
+}
+
+// withTransaction executa fn em uma transação, repetindo-a enquanto o banco estiver travado,
+// como o execWithRetry faz para statements avulsos. Qualquer erro de fn desfaz a transação.
+func withTransaction(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	const maxRetries = 20
+	const baseDelay = 100 * time.Millisecond
+
+	var err error
+	for i := 0; i < maxRetries; i++ {
+		err = runTransaction(db, fn)
+		if err == nil || !isBusyError(err) {
+			return err
+		}
+		time.Sleep(baseDelay * time.Duration(i+1))
+	}
+
+	return fmt.Errorf("falha após %d tentativas (banco travado): %w", maxRetries, err)
+}
+
+func runTransaction(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByIdempotencyKey busca a submissão mais recente do cliente criada com a chave desde since,
+// devolvendo seu id e a impressão digital da requisição. Retorna customErrors.ErrNotFound se não houver.
+func (r *SubmissionRepository) FindByIdempotencyKey(clientID, key string, since time.Time) (string, string, error) {
+	query := `SELECT id, COALESCE(idempotency_hash, '') FROM submissions
+              WHERE idempotency_key = ? AND COALESCE(api_key_id, '') = ? AND created_at >= ?
+              ORDER BY created_at DESC LIMIT 1`
+
+	var id, hash string
+	err := r.DB.QueryRow(query, key, clientID, since.UnixMilli()).Scan(&id, &hash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", customErrors.ErrNotFound
+		}
+		return "", "", fmt.Errorf("falha ao buscar idempotency_key: %w", err)
+	}
+	return id, hash, nil
+}
+
+// CountActiveByClient conta os jobs queued ou processing criados com a chave de API informada.
+func (r *SubmissionRepository) CountActiveByClient(clientID string) (int, error) {
+	var count int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM submissions WHERE api_key_id = ? AND status IN (?, ?)`,
+		clientID, models.StatusQueued, models.StatusProcessing).Scan(&count)
+	return count, err
+}
+
+// CachePathsInUse devolve os pacotes (Job.CachePath) que os jobs queued ou processing do problema
+// ainda vão copiar.
+func (r *SubmissionRepository) CachePathsInUse(problemID string) ([]string, error) {
+	rows, err := r.DB.Query(`SELECT DISTINCT json_extract(job_data, '$.CachePath') FROM submissions
+              WHERE problem_id = ? AND status IN (?, ?)`,
+		problemID, models.StatusQueued, models.StatusProcessing)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar pacotes em uso: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path sql.NullString
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("falha ao ler pacote em uso: %w", err)
+		}
+		if path.Valid {
+			paths = append(paths, path.String)
+		}
+	}
+	return paths, rows.Err()
+}
+
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// ClaimNextJob reserva atomicamente o job mais antigo da lane informada para o owner.
+// Jobs em processing cujo lease expirou (worker morto ou processo reiniciado) também podem ser reservados.
+// Cada reserva incrementa attempts, devolvido em Job.Attempts.
+// Retorna customErrors.ErrNotFound quando a lane está vazia.
+func (r *SubmissionRepository) ClaimNextJob(owner string, priority models.Priority, lease time.Duration) (models.Job, error) {
+	query := `UPDATE submissions
+              SET status = ?, lease_owner = ?, lease_expires_at = ?, updated_at = ?, attempts = attempts + 1
+              WHERE id = (
+                  SELECT id FROM submissions
+                  WHERE priority = ?
+                    AND (status = ?
+                         OR (status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)))
+                  ORDER BY rowid
+                  LIMIT 1
+              )
+              RETURNING job_data, attempts`
+
+	const maxRetries = 20
+	const baseDelay = 100 * time.Millisecond
+
+	var err error
+	for i := 0; i < maxRetries; i++ {
+		now := time.Now()
+
+		var jobDataString string
+		var attempts int
+		err = r.DB.QueryRow(query,
+			models.StatusProcessing, owner, now.Add(lease).UnixMilli(), now,
+			string(priority), models.StatusQueued, models.StatusProcessing, now.UnixMilli(),
+		).Scan(&jobDataString, &attempts)
+
+		if err == nil {
+			var job models.Job
+			if err := json.Unmarshal([]byte(jobDataString), &job); err != nil {
+				return models.Job{}, fmt.Errorf("falha ao desserializar job data: %w", err)
+			}
+			job.Attempts = attempts
+			return job, nil
+		}
+
+		if err == sql.ErrNoRows {
+			return models.Job{}, customErrors.ErrNotFound
+		}
+
+		if isBusyError(err) {
+			time.Sleep(baseDelay * time.Duration(i+1))
+			continue
+		}
+
+		return models.Job{}, err
+	}
+
+	return models.Job{}, fmt.Errorf("falha após %d tentativas (banco travado): %w", maxRetries, err)
+}
+
+// CancelQueued encerra como error, com a mensagem informada, um job que ainda está na fila,
+// e devolve o job cancelado. Com clientID preenchido, só cancela jobs desse cliente.
+// Retorna customErrors.ErrNotFound se o job não existir ou for de outro cliente e
+// customErrors.ErrNotCancelable se algum worker já o reservou ou ele já terminou.
+func (r *SubmissionRepository) CancelQueued(id, clientID, message string) (models.Job, error) {
+	query := `UPDATE submissions
+              SET status = ?, error_message = ?, updated_at = ?
+              WHERE id = ? AND status = ? AND (? = '' OR api_key_id = ?)
+              RETURNING job_data`
+
+	const maxRetries = 20
+	const baseDelay = 100 * time.Millisecond
+
+	var err error
+	for i := 0; i < maxRetries; i++ {
+		var jobDataString string
+		err = r.DB.QueryRow(query, models.StatusError, message, time.Now(), id, models.StatusQueued, clientID, clientID).Scan(&jobDataString)
+
+		if err == nil {
+			var job models.Job
+			if err := json.Unmarshal([]byte(jobDataString), &job); err != nil {
+				return models.Job{}, fmt.Errorf("falha ao desserializar job data: %w", err)
+			}
+			return job, nil
+		}
+
+		if err == sql.ErrNoRows {
+			var owner sql.NullString
+			err := r.DB.QueryRow(`SELECT api_key_id FROM submissions WHERE id = ?`, id).Scan(&owner)
+			if err == sql.ErrNoRows || (err == nil && clientID != "" && owner.String != clientID) {
+				// a submissão de outro cliente não tem a existência revelada
+				return models.Job{}, customErrors.ErrNotFound
+			}
+			if err != nil {
+				return models.Job{}, fmt.Errorf("falha ao buscar job: %w", err)
+			}
+			return models.Job{}, customErrors.ErrNotCancelable
+		}
+
+		if isBusyError(err) {
+			time.Sleep(baseDelay * time.Duration(i+1))
+			continue
+		}
+
+		return models.Job{}, fmt.Errorf("falha ao cancelar job: %w", err)
+	}
+
+	return models.Job{}, fmt.Errorf("falha após %d tentativas (banco travado): %w", maxRetries, err)
+}
+
+// RenewLease estende o lease de um job que ainda pertence ao owner.
+// Retorna customErrors.ErrNotFound se o lease foi perdido para outro worker.
+func (r *SubmissionRepository) RenewLease(id, owner string, lease time.Duration) error {
+	query := `UPDATE submissions SET lease_expires_at = ?
+              WHERE id = ? AND lease_owner = ? AND status = ?`
+
+	res, err := execWithRetry(r.DB, query, time.Now().Add(lease).UnixMilli(), id, owner, models.StatusProcessing)
+	if err != nil {
+		return fmt.Errorf("falha ao renovar lease: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("falha ao renovar lease: %w", err)
+	}
+	if affected == 0 {
+		return customErrors.ErrNotFound
+	}
+
+	return nil
+}
+
+// RequeueJob devolve para a fila um job que o owner não conseguiu terminar (ex.: desligamento do processo).
+// A reserva devolvida não conta como tentativa.
+func (r *SubmissionRepository) RequeueJob(id, owner string) error {
+	query := `UPDATE submissions
+              SET status = ?, lease_owner = NULL, lease_expires_at = NULL, updated_at = ?, attempts = MAX(attempts - 1, 0)
+              WHERE id = ? AND lease_owner = ? AND status = ?`
+
+	if _, err := execWithRetry(r.DB, query, models.StatusQueued, time.Now(), id, owner, models.StatusProcessing); err != nil {
+		return fmt.Errorf("falha ao devolver job para a fila: %w", err)
+	}
+
+	return nil
+}
+
+// GetLeasedJobIDs retorna os jobs em processing com lease ainda válido, ou seja, que algum worker está executando.
+func (r *SubmissionRepository) GetLeasedJobIDs() (map[string]bool, error) {
+	query := `SELECT id FROM submissions WHERE status = ? AND lease_expires_at >= ?`
+
+	rows, err := r.DB.Query(query, models.StatusProcessing, time.Now().UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *SubmissionRepository) CountByStatus(status string) (int, error) {
+	var count int
+	err := r.DB.QueryRow(`SELECT COUNT(*) FROM submissions WHERE status = ?`, status).Scan(&count)
+	return count, err
+}
+
+// CountQueuedByPriority conta os jobs queued de cada lane de prioridade.
+func (r *SubmissionRepository) CountQueuedByPriority() (map[string]int, error) {
+	rows, err := r.DB.Query(`SELECT priority, COUNT(*) FROM submissions WHERE status = ? GROUP BY priority`, models.StatusQueued)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for _, priority := range models.Priorities {
+		counts[string(priority)] = 0
+	}
+	for rows.Next() {
+		var priority string
+		var count int
+		if err := rows.Scan(&priority, &count); err != nil {
+			return nil, err
+		}
+		counts[priority] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// UpdateResult grava o resultado de um job que ainda pertence ao owner.
+// Retorna customErrors.ErrNotFound se o lease foi perdido para outro worker.
+func (r *SubmissionRepository) UpdateResult(result models.JobResult, owner string) error {
 	resultJSON, err := json.Marshal(result.Result)
 	if err != nil {
 		return fmt.Errorf("falha ao serializar resultado: %w", err)
 	}
 
 	query := `UPDATE submissions 
-              SET status = ?, result_json = ?, error_message = ?, updated_at = ? 
-              WHERE id = ?`
+              SET status = ?, result_json = ?, error_message = ?, updated_at = ?,
+                  lease_owner = NULL, lease_expires_at = NULL
+              WHERE id = ? AND lease_owner = ?`
 
-	if err := r.execWithRetry(query, result.Status, string(resultJSON), result.ErrorMessage, time.Now(), result.ID); err != nil {
+	res, err := execWithRetry(r.DB, query, result.Status, string(resultJSON), result.ErrorMessage, time.Now(), result.ID, owner)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar job: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
 		return fmt.Errorf("falha ao atualizar job: %w", err)
 	}
+	if affected == 0 {
+		return customErrors.ErrNotFound
+	}
 
 	return nil
 }
 
 func (r *SubmissionRepository) GetByID(id string) (models.JobResult, error) {
-	query := `SELECT id, status, result_json, error_message FROM submissions WHERE id = ?`
+	query := `SELECT id, status, result_json, error_message, COALESCE(json_extract(job_data, '$.Kind'), '') FROM submissions WHERE id = ?`
 	row := r.DB.QueryRow(query, id)
 
 	var res models.JobResult
 	var jsonString string
 
-	err := row.Scan(&res.ID, &res.Status, &jsonString, &res.ErrorMessage)
+	err := row.Scan(&res.ID, &res.Status, &jsonString, &res.ErrorMessage, &res.Kind)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return models.JobResult{}, customErrors.ErrNotFound
@@ -116,29 +646,115 @@ func (r *SubmissionRepository) GetByID(id string) (models.JobResult, error) {
 	return res, nil
 }
 
-func (r *SubmissionRepository) GetRecoverableJobs() ([]models.Job, error) {
-	query := `SELECT job_data FROM submissions WHERE status IN (?, ?)`
+// ListSubmissions busca submissões com os filtros informados, ordenadas por data de criação.
+// A paginação é por cursor (created_at, rowid), estável mesmo com novas submissões chegando.
+func (r *SubmissionRepository) ListSubmissions(filter models.SubmissionFilter) (models.SubmissionPage, error) {
+	var conditions []string
+	var args []interface{}
 
-	rows, err := r.DB.Query(query, models.StatusQueued, models.StatusProcessing)
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.ProblemID != "" {
+		conditions = append(conditions, "problem_id = ?")
+		args = append(args, filter.ProblemID)
+	}
+	if filter.Language != "" {
+		conditions = append(conditions, "language = ?")
+		args = append(args, filter.Language)
+	}
+	if filter.Priority != "" {
+		conditions = append(conditions, "priority = ?")
+		args = append(args, filter.Priority)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From.UnixMilli())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To.UnixMilli())
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.Cursor != "" {
+		createdAt, rowID, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return models.SubmissionPage{}, err
+		}
+		conditions = append(conditions, fmt.Sprintf(
+			"(COALESCE(created_at, 0) %[1]s ? OR (COALESCE(created_at, 0) = ? AND rowid %[1]s ?))", comparison))
+		args = append(args, createdAt, createdAt, rowID)
+	}
+
+	query := `SELECT rowid, id, COALESCE(problem_id, ''), COALESCE(language, ''), priority, status,
+                     COALESCE(error_message, ''), COALESCE(created_at, 0), updated_at
+              FROM submissions`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY COALESCE(created_at, 0) %[1]s, rowid %[1]s LIMIT ?", direction)
+	// um registro a mais para saber se existe próxima página
+	args = append(args, filter.Limit+1)
+
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return models.SubmissionPage{}, err
 	}
 	defer rows.Close()
 
-	var jobs []models.Job
+	page := models.SubmissionPage{Submissions: []models.SubmissionSummary{}}
+	var lastCreatedAt, lastRowID int64
 	for rows.Next() {
-		var jobDataString string
-		if err := rows.Scan(&jobDataString); err != nil {
-			return nil, err
+		if len(page.Submissions) == filter.Limit {
+			page.NextCursor = encodeCursor(lastCreatedAt, lastRowID)
+			break
 		}
 
-		var job models.Job
-		if err := json.Unmarshal([]byte(jobDataString), &job); err != nil {
-			fmt.Printf("Erro ao recuperar job (JSON corrompido): %v\n", err)
-			continue
+		var summary models.SubmissionSummary
+		var createdAt int64
+		if err := rows.Scan(&lastRowID, &summary.ID, &summary.ProblemID, &summary.Language, &summary.Priority,
+			&summary.Status, &summary.ErrorMessage, &createdAt, &summary.UpdatedAt); err != nil {
+			return models.SubmissionPage{}, err
 		}
-		jobs = append(jobs, job)
+		lastCreatedAt = createdAt
+		if createdAt > 0 {
+			summary.CreatedAt = time.UnixMilli(createdAt)
+		}
+		page.Submissions = append(page.Submissions, summary)
+	}
+
+	return page, rows.Err()
+}
+
+func encodeCursor(createdAt, rowID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", createdAt, rowID)))
+}
+
+func decodeCursor(cursor string) (int64, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, customErrors.ErrInvalidCursor
+	}
+
+	createdAtStr, rowIDStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return 0, 0, customErrors.ErrInvalidCursor
+	}
+
+	createdAt, err := strconv.ParseInt(createdAtStr, 10, 64)
+	if err != nil {
+		return 0, 0, customErrors.ErrInvalidCursor
+	}
+	rowID, err := strconv.ParseInt(rowIDStr, 10, 64)
+	if err != nil {
+		return 0, 0, customErrors.ErrInvalidCursor
 	}
 
-	return jobs, nil
+	return createdAt, rowID, nil
 }
@@ -0,0 +1,148 @@
+package repository_test
+
+import (
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/internal/repository"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func newSubmissionRepository(t *testing.T) *repository.SubmissionRepository {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "judger.db"))
+	if err != nil {
+		t.Fatalf("abrir banco: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo, err := repository.StartSubmissionRepository(db)
+	if err != nil {
+		t.Fatalf("StartSubmissionRepository: %v", err)
+	}
+	return repo
+}
+
+func enqueue(t *testing.T, repo *repository.SubmissionRepository, id string, priority models.Priority) {
+	t.Helper()
+	job := models.Job{ID: id, ProblemID: "p1", Priority: priority, Code: "print(1)"}
+	if err := repo.CreateJob(job, 100, 0, time.Time{}); err != nil {
+		t.Fatalf("CreateJob(%s): %v", id, err)
+	}
+}
+
+func TestClaimNextJob(t *testing.T) {
+	repo := newSubmissionRepository(t)
+	enqueue(t, repo, "a", models.PriorityPractice)
+	enqueue(t, repo, "b", models.PriorityContest)
+	enqueue(t, repo, "c", models.PriorityPractice)
+
+	// cada lane entrega os próprios jobs, do mais antigo para o mais novo
+	for _, want := range []string{"a", "c"} {
+		job, err := repo.ClaimNextJob("worker-1", models.PriorityPractice, time.Minute)
+		if err != nil {
+			t.Fatalf("ClaimNextJob: %v", err)
+		}
+		if job.ID != want || job.Attempts != 1 {
+			t.Errorf("job reservado = %s (attempts %d), esperado %s (attempts 1)", job.ID, job.Attempts, want)
+		}
+	}
+
+	// os jobs reservados, com lease válido, não são entregues de novo
+	if _, err := repo.ClaimNextJob("worker-2", models.PriorityPractice, time.Minute); !errors.Is(err, customErrors.ErrNotFound) {
+		t.Errorf("lane vazia: err = %v, esperado ErrNotFound", err)
+	}
+
+	job, err := repo.ClaimNextJob("worker-2", models.PriorityContest, time.Minute)
+	if err != nil || job.ID != "b" {
+		t.Errorf("lane contest: job = %q, err = %v", job.ID, err)
+	}
+
+	status, err := repo.GetByID("a")
+	if err != nil || status.Status != models.StatusProcessing {
+		t.Errorf("status do job reservado = %q (err %v), esperado %q", status.Status, err, models.StatusProcessing)
+	}
+}
+
+func TestLeaseExpiryAndReclaim(t *testing.T) {
+	repo := newSubmissionRepository(t)
+	enqueue(t, repo, "a", models.PriorityPractice)
+
+	if _, err := repo.ClaimNextJob("worker-1", models.PriorityPractice, time.Millisecond); err != nil {
+		t.Fatalf("ClaimNextJob: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// o lease do worker-1 expirou: outro worker retoma o job, e a reserva conta outra tentativa
+	job, err := repo.ClaimNextJob("worker-2", models.PriorityPractice, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimNextJob após expirar: %v", err)
+	}
+	if job.ID != "a" || job.Attempts != 2 {
+		t.Errorf("job retomado = %s (attempts %d), esperado a (attempts 2)", job.ID, job.Attempts)
+	}
+
+	// o antigo dono descobre na renovação que perdeu o lease, e o resultado dele é recusado
+	if err := repo.RenewLease("a", "worker-1", time.Minute); !errors.Is(err, customErrors.ErrNotFound) {
+		t.Errorf("RenewLease do antigo dono: err = %v, esperado ErrNotFound", err)
+	}
+	stale := models.JobResult{ID: "a", Status: models.StatusError, ErrorMessage: "resultado antigo"}
+	if err := repo.UpdateResult(stale, "worker-1"); !errors.Is(err, customErrors.ErrNotFound) {
+		t.Errorf("UpdateResult do antigo dono: err = %v, esperado ErrNotFound", err)
+	}
+
+	if err := repo.RenewLease("a", "worker-2", time.Minute); err != nil {
+		t.Errorf("RenewLease do novo dono: %v", err)
+	}
+	if err := repo.UpdateResult(models.JobResult{ID: "a", Status: models.StatusSuccess}, "worker-2"); err != nil {
+		t.Fatalf("UpdateResult do novo dono: %v", err)
+	}
+
+	result, err := repo.GetByID("a")
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if result.Status != models.StatusSuccess || result.ErrorMessage != "" {
+		t.Errorf("resultado gravado = %q (%q), esperado o do novo dono", result.Status, result.ErrorMessage)
+	}
+
+	// um job terminado não volta para a fila, mesmo sem lease
+	if _, err := repo.ClaimNextJob("worker-3", models.PriorityPractice, time.Minute); !errors.Is(err, customErrors.ErrNotFound) {
+		t.Errorf("job terminado reservado de novo: err = %v", err)
+	}
+}
+
+func TestRequeueJobKeepsAttempts(t *testing.T) {
+	repo := newSubmissionRepository(t)
+	enqueue(t, repo, "a", models.PriorityPractice)
+
+	if _, err := repo.ClaimNextJob("worker-1", models.PriorityPractice, time.Minute); err != nil {
+		t.Fatalf("ClaimNextJob: %v", err)
+	}
+
+	// só o dono do lease devolve o job
+	if err := repo.RequeueJob("a", "worker-2"); err != nil {
+		t.Fatalf("RequeueJob de outro worker: %v", err)
+	}
+	if _, err := repo.ClaimNextJob("worker-2", models.PriorityPractice, time.Minute); !errors.Is(err, customErrors.ErrNotFound) {
+		t.Errorf("job devolvido por quem não é dono: err = %v", err)
+	}
+
+	if err := repo.RequeueJob("a", "worker-1"); err != nil {
+		t.Fatalf("RequeueJob: %v", err)
+	}
+	job, err := repo.ClaimNextJob("worker-2", models.PriorityPractice, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimNextJob após devolver: %v", err)
+	}
+	// a reserva devolvida no desligamento não conta como tentativa
+	if job.Attempts != 1 {
+		t.Errorf("attempts = %d, esperado 1", job.Attempts)
+	}
+}
@@ -1,16 +1,37 @@
 package router
 
 import (
+	"IFJudger/internal/api/apierror"
+	"IFJudger/internal/api/openapi"
 	"IFJudger/internal/controllers"
+	"IFJudger/internal/middleware"
+	"IFJudger/internal/models"
 	"IFJudger/internal/models/configs"
 	"IFJudger/internal/repository"
 	"IFJudger/internal/services"
 	"IFJudger/pkg/config"
+	"IFJudger/pkg/judgerpb"
+	"IFJudger/pkg/metrics"
+	"context"
 	"database/sql"
+	"errors"
 	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
 )
 
-func StartRoutes(config *config.Config, db *sql.DB) *http.ServeMux {
+// prefixo das rotas da versão atual da API
+const apiVersionPrefix = "/v1"
+
+// prazo para o envio de callbacks em andamento terminar, contado depois da drenagem dos workers
+const callbackShutdownTimeout = 5 * time.Second
+
+// StartRoutes monta as dependências, as rotas da API HTTP e o servidor gRPC, que compartilham os serviços.
+// A função retornada drena os workers e deve ser chamada no desligamento, antes de fechar os servidores.
+func StartRoutes(config *config.Config, db *sql.DB) (*http.ServeMux, *grpc.Server, func(context.Context) error) {
 	mux := http.NewServeMux()
 
 	testController, err := controllers.StartTestController()
@@ -18,35 +39,95 @@ func StartRoutes(config *config.Config, db *sql.DB) *http.ServeMux {
 		panic(err.Error())
 	}
 
+	submissionRepository, err := repository.StartSubmissionRepository(db)
+	if err != nil {
+		panic(err.Error())
+	}
+
 	cacheService, err := services.StartCacheService(configs.ConfigCache{
 		ONLYLOCAL:          config.OnlyLocalCache,
 		APIURL:             config.APIUrl,
 		APIKEY:             config.APIKey,
 		CACHEDIRECTORY:     config.CacheDirectory,
 		CACHEFILEEXTENSION: config.CacheFileExtension,
-	})
+		MAXPACKAGEBYTES:    int64(config.ProblemPackageMaxMB) << 20,
+		MAXFILEBYTES:       int64(config.ProblemFileMaxMB) << 20,
+		MAXEXTRACTEDBYTES:  int64(config.ProblemExtractedMaxMB) << 20,
+		REVALIDATEINTERVAL: config.CacheRevalidateInterval,
+		APITIMEOUT:         config.CacheAPITimeout,
+	}, submissionRepository)
 	if err != nil {
 		panic(err.Error())
 	}
 
-	submissionRepository, err := repository.StartSubmissionRepository(db)
+	apiKeyRepository, err := repository.StartAPIKeyRepository(db)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	authService, err := services.StartAuthService(apiKeyRepository, config.BootstrapAPIKey)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	auth, err := middleware.StartAuth(authService, config.AuthEnabled)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	callbackRepository, err := repository.StartCallbackRepository(db)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	callbackService, err := services.StartCallbackService(configs.CallbackServiceConfig{
+		CallbackUrl:      config.CallbackUrl,
+		Secret:           config.CallbackSecret,
+		MaxAttempts:      config.CallbackMaxAttempts,
+		ProcessingEvents: config.CallbackProcessingEvents,
+		BaseBackoff:      config.CallbackBaseBackoff,
+		MaxBackoff:       config.CallbackMaxBackoff,
+		RequestTimeout:   config.CallbackTimeout,
+	}, callbackRepository)
 	if err != nil {
 		panic(err.Error())
 	}
 
 	workerService, err := services.StartWorkerService(configs.WorkerServiceConfig{
-		ExecutionDirectory: config.ExecutionDirectory,
-		CallbackUrl:        config.CallbackUrl,
-		RunnerPath:         config.RunnerBinaryPath,
-		ContainerTimeout:   config.ContainerTimeout,
-		MaxWorkers:         config.MaxWorkers,
-		QueueSize:          config.QueueSize,
-	}, submissionRepository)
+		ExecutionDirectory:  config.ExecutionDirectory,
+		RunnerPath:          config.RunnerBinaryPath,
+		ContainerTimeout:    config.ContainerTimeout,
+		LeaseTimeout:        config.LeaseTimeout,
+		MaxAttempts:         config.JobMaxAttempts,
+		IdempotencyTTL:      config.IdempotencyTTL,
+		ReaperInterval:      config.ReaperInterval,
+		MaxWorkers:          config.MaxWorkers,
+		QueueSize:           config.QueueSize,
+		LaneWeights:         config.LaneWeights,
+		LaneReservedWorkers: config.LaneReservedWorkers,
+	}, submissionRepository, callbackService)
 	if err != nil {
 		panic(err.Error())
 	}
 
-	judgerService, err := services.StartJudgerService(workerService, cacheService)
+	judgerService, err := services.StartJudgerService(configs.JudgerServiceConfig{
+		AllowedWebhookHosts: config.AllowedWebhookHosts,
+		RunTimeLimit:        config.RunTimeLimit,
+		RunMemoryLimitMB:    config.RunMemoryLimitMB,
+		RunOutputLimitBytes: config.RunOutputLimitKB * 1024,
+		BatchMaxSize:        config.BatchMaxSize,
+	}, workerService, cacheService)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	healthService, err := services.StartHealthService(configs.HealthServiceConfig{
+		CacheDirectory:         config.CacheDirectory,
+		RunnerPath:             config.RunnerBinaryPath,
+		QueueSize:              config.QueueSize,
+		QueueSaturationPercent: config.ReadyQueuePercent,
+		CheckTimeout:           config.ReadyCheckTimeout,
+	}, db, workerService)
 	if err != nil {
 		panic(err.Error())
 	}
@@ -56,9 +137,91 @@ func StartRoutes(config *config.Config, db *sql.DB) *http.ServeMux {
 		panic(err.Error())
 	}
 
+	callbackController, err := controllers.StartCallbackController(callbackService)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	apiKeyController, err := controllers.StartAPIKeyController(authService)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	problemController, err := controllers.StartProblemController(cacheService)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	healthController, err := controllers.StartHealthController(healthService)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	// as rotas da API ficam em /v1; o mesmo caminho sem versão continua respondendo, marcado como obsoleto.
+	// Cada requisição gera um span nomeado pelo padrão da rota.
+	route := func(method, path string, handler http.HandlerFunc) {
+		pattern := method + " " + apiVersionPrefix + path
+		mux.Handle(pattern, otelhttp.NewHandler(handler, pattern))
+
+		legacy := method + " " + path
+		mux.Handle(legacy, otelhttp.NewHandler(middleware.Deprecated(apiVersionPrefix, handler), legacy))
+	}
+
 	mux.HandleFunc("GET /test", testController.GetTest)
-	mux.HandleFunc("POST /submit", judgerController.HandleSubmission)
-	mux.HandleFunc("GET /job", judgerController.HandleStatus)
+	// rotas operacionais (probes e métricas) ficam fora da versão da API e sem tracing
+	mux.HandleFunc("GET /healthz", healthController.HandleLiveness)
+	mux.HandleFunc("GET /readyz", healthController.HandleReadiness)
+	mux.Handle("GET /metrics", metrics.Handler())
+	route("GET", "/openapi.json", openapi.Handle)
+	route("POST", "/submit", auth.Require(models.ScopeSubmit, judgerController.HandleSubmission))
+	route("POST", "/submit/batch", auth.Require(models.ScopeSubmit, judgerController.HandleBatchSubmission))
+	route("GET", "/batches/{id}", auth.Require(models.ScopeRead, judgerController.HandleBatchStatus))
+	route("GET", "/job", auth.Require(models.ScopeRead, judgerController.HandleStatus))
+	route("GET", "/job/{id}/events", auth.Require(models.ScopeRead, judgerController.HandleEvents))
+	route("GET", "/submissions", auth.Require(models.ScopeRead, judgerController.HandleList))
+	route("POST", "/run", auth.Require(models.ScopeSubmit, judgerController.HandleRun))
+	route("GET", "/run/{id}", auth.Require(models.ScopeRead, judgerController.HandleRunStatus))
+	route("GET", "/languages", auth.Require(models.ScopeRead, judgerController.HandleLanguages))
+	route("GET", "/problems/{id}/languages", auth.Require(models.ScopeRead, judgerController.HandleProblemLanguages))
+	route("POST", "/problems/{id}", auth.Require(models.ScopeAdmin, problemController.HandleUpload))
+	route("DELETE", "/problems/{id}", auth.Require(models.ScopeAdmin, problemController.HandleInvalidate))
+	route("GET", "/callbacks", auth.Require(models.ScopeAdmin, callbackController.HandleList))
+	route("POST", "/callbacks/{id}/retry", auth.Require(models.ScopeAdmin, callbackController.HandleRetry))
+	route("POST", "/admin/keys", auth.Require(models.ScopeAdmin, apiKeyController.HandleCreate))
+	route("GET", "/admin/keys", auth.Require(models.ScopeAdmin, apiKeyController.HandleList))
+	route("DELETE", "/admin/keys/{id}", auth.Require(models.ScopeAdmin, apiKeyController.HandleRevoke))
+	mux.HandleFunc(apiVersionPrefix+"/", apierror.HandleRouteNotFound)
+
+	judgerGRPCController, err := controllers.StartJudgerGRPCController(judgerService)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	// escopo exigido por método gRPC, como nas rotas HTTP equivalentes
+	grpcScopes := map[string]string{
+		judgerpb.Judger_Submit_FullMethodName:          models.ScopeSubmit,
+		judgerpb.Judger_GetStatus_FullMethodName:       models.ScopeRead,
+		judgerpb.Judger_WatchSubmission_FullMethodName: models.ScopeRead,
+		judgerpb.Judger_Cancel_FullMethodName:          models.ScopeSubmit,
+		judgerpb.Judger_ListLanguages_FullMethodName:   models.ScopeRead,
+	}
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(auth.UnaryInterceptor(grpcScopes)),
+		grpc.StreamInterceptor(auth.StreamInterceptor(grpcScopes)),
+	)
+	judgerpb.RegisterJudgerServer(grpcServer, judgerGRPCController)
+
+	shutdown := func(ctx context.Context) error {
+		// os workers primeiro, para que os resultados dos últimos jobs ainda entrem no outbox
+		workersErr := workerService.Shutdown(ctx)
+
+		// prazo próprio: a drenagem pode ter consumido todo o ctx, e os callbacks ficariam sem tempo
+		callbacksCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), callbackShutdownTimeout)
+		defer cancel()
+		callbacksErr := callbackService.Shutdown(callbacksCtx)
+		return errors.Join(workersErr, callbacksErr)
+	}
 
-	return mux
+	return mux, grpcServer, shutdown
 }
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/internal/repository"
+	"IFJudger/pkg/logger"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// prefixo das chaves geradas pelo judger, para facilitar identificá-las em logs e configs
+const apiKeyPrefix = "ifj_"
+
+type AuthService struct {
+	repository *repository.APIKeyRepository
+	limiter    *rateLimiter
+}
+
+func StartAuthService(repository *repository.APIKeyRepository, bootstrapKey string) (*AuthService, error) {
+	service := &AuthService{
+		repository: repository,
+		limiter:    newRateLimiter(),
+	}
+
+	if bootstrapKey != "" {
+		if err := service.ensureBootstrapKey(bootstrapKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return service, nil
+}
+
+// ensureBootstrapKey cadastra a chave de administração definida na configuração,
+// para que seja possível criar as demais chaves pela API. Se ela foi revogada, continua revogada.
+func (s *AuthService) ensureBootstrapKey(rawKey string) error {
+	keyHash := hashAPIKey(rawKey)
+
+	exists, err := s.repository.ExistsByHash(keyHash)
+	if err != nil {
+		return fmt.Errorf("failed to check bootstrap key: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	key := models.APIKey{
+		ID:        generateToken()[:16],
+		Name:      "bootstrap",
+		Scopes:    []string{models.ScopeAdmin},
+		CreatedAt: time.Now(),
+	}
+	if err := s.repository.Create(key, keyHash); err != nil {
+		return err
+	}
+
+	logger.Component("auth").Info("Chave de bootstrap cadastrada", "key_id", key.ID)
+	return nil
+}
+
+// Authenticate valida a chave apresentada. O rate limit fica a cargo de quem chama, via Allow,
+// para que o HTTP e o gRPC respondam o excesso cada um no seu formato.
+func (s *AuthService) Authenticate(rawKey string) (models.APIKey, error) {
+	if rawKey == "" {
+		return models.APIKey{}, customErrors.ErrUnauthorized
+	}
+
+	key, err := s.repository.FindActiveByHash(hashAPIKey(rawKey))
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return models.APIKey{}, customErrors.ErrUnauthorized
+		}
+		return models.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// Allow consome uma requisição do rate limit da chave, devolvendo quanto esperar quando excedido.
+func (s *AuthService) Allow(key models.APIKey) (bool, time.Duration) {
+	return s.limiter.allow(key.ID, key.RateLimitPerMinute)
+}
+
+// CreateKey gera uma nova chave. O valor em texto só é devolvido aqui; o banco guarda apenas o hash.
+func (s *AuthService) CreateKey(name string, scopes []string, rateLimitPerMinute, maxActiveJobs int) (models.APIKey, string, error) {
+	if name == "" {
+		return models.APIKey{}, "", fmt.Errorf("%w: name is required", customErrors.ErrValidation)
+	}
+	if len(scopes) == 0 {
+		return models.APIKey{}, "", fmt.Errorf("%w: at least one scope is required", customErrors.ErrValidation)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(models.Scopes, scope) {
+			return models.APIKey{}, "", fmt.Errorf("%w: invalid scope %q", customErrors.ErrValidation, scope)
+		}
+	}
+	if rateLimitPerMinute < 0 || maxActiveJobs < 0 {
+		return models.APIKey{}, "", fmt.Errorf("%w: limits cannot be negative", customErrors.ErrValidation)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return models.APIKey{}, "", err
+	}
+	rawKey := apiKeyPrefix + hex.EncodeToString(secret)
+
+	key := models.APIKey{
+		ID:                 generateToken()[:16],
+		Name:               name,
+		Scopes:             scopes,
+		RateLimitPerMinute: rateLimitPerMinute,
+		MaxActiveJobs:      maxActiveJobs,
+		CreatedAt:          time.Now(),
+	}
+	if err := s.repository.Create(key, hashAPIKey(rawKey)); err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	return key, rawKey, nil
+}
+
+func (s *AuthService) ListKeys() ([]models.APIKey, error) {
+	return s.repository.List()
+}
+
+func (s *AuthService) RevokeKey(id string) error {
+	return s.repository.Revoke(id)
+}
+
+// as chaves são aleatórias com 256 bits, então SHA-256 sem salt basta para guardá-las
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
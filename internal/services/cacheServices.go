@@ -3,45 +3,110 @@ package services
 import (
 	"IFJudger/internal/models"
 	"IFJudger/internal/models/configs"
-	folderutils "IFJudger/pkg/folder_utils"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/internal/repository"
+	"IFJudger/pkg/logger"
+	"IFJudger/pkg/metrics"
+	"IFJudger/pkg/tracing"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type CacheService struct {
 	cacheConfig configs.ConfigCache
+	repository  *repository.SubmissionRepository
+	logger      *slog.Logger
+	httpClient  *http.Client
+	// serializa as instalações de pacotes (download ou upload) e as escritas nos manifestos
+	installMu sync.Mutex
+
+	revalidatingMu sync.Mutex
+	revalidating   map[string]bool
 }
 
-func StartCacheService(cacheConfig configs.ConfigCache) (*CacheService, error) {
+func StartCacheService(cacheConfig configs.ConfigCache, repository *repository.SubmissionRepository) (*CacheService, error) {
 	if err := os.MkdirAll(cacheConfig.CACHEDIRECTORY, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create cache root: %w", err)
 	}
 	return &CacheService{
-		cacheConfig: cacheConfig,
+		cacheConfig:  cacheConfig,
+		repository:   repository,
+		logger:       logger.Component("cache"),
+		httpClient:   &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		revalidating: map[string]bool{},
 	}, nil
 }
 
-func (s *CacheService) GetProblemData(problemID string) ([]models.LanguageLimits, string, error) {
+func (s *CacheService) GetProblemData(ctx context.Context, problemID string) ([]models.LanguageLimits, string, error) {
+	ctx, span := tracing.Start(ctx, "cache.lookup", trace.WithAttributes(attribute.String(logger.KeyProblemID, problemID)))
+	limits, problemDir, err := s.getProblemData(ctx, problemID)
+	tracing.End(span, err)
+	return limits, problemDir, err
+}
+
+func (s *CacheService) getProblemData(ctx context.Context, problemID string) ([]models.LanguageLimits, string, error) {
+	// o id vira caminho no cache: "../x" escaparia do diretório
+	if !problemIDPattern.MatchString(problemID) {
+		return nil, "", fmt.Errorf("%w: invalid problem id %q", customErrors.ErrProblemNotFound, problemID)
+	}
+
 	problemDir := filepath.Join(s.cacheConfig.CACHEDIRECTORY, problemID+s.cacheConfig.CACHEFILEEXTENSION)
 
 	metaPath := filepath.Join(problemDir, "meta.json")
 	_, err := os.Stat(metaPath)
 	if os.IsNotExist(err) {
+		metrics.CacheLookups.WithLabelValues(metrics.CacheMiss).Inc()
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cache.hit", false))
 		if s.cacheConfig.ONLYLOCAL {
-			return nil, "", fmt.Errorf("problem %s not found in local cache", problemID)
+			return nil, "", fmt.Errorf("%w: %s is not in the local cache", customErrors.ErrProblemNotFound, problemID)
 		}
 
-		err = s.downloadAndExtract(problemID, problemDir)
+		start := time.Now()
+		s.logger.Info("Problema ausente no cache, baixando", logger.KeyProblemID, problemID)
+		_, err = s.downloadPackage(ctx, problemID, packageManifest{})
+		outcome := "success"
 		if err != nil {
+			outcome = "error"
+		}
+		metrics.CacheDownloadDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+		if err != nil {
+			s.logger.Error("Falha ao baixar pacote do problema", logger.KeyProblemID, problemID, logger.Err(err))
 			return nil, "", err
 		}
+		s.logger.Info("Pacote do problema baixado", logger.KeyProblemID, problemID, "duration_ms", time.Since(start).Milliseconds())
+	} else {
+		metrics.CacheLookups.WithLabelValues(metrics.CacheHit).Inc()
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cache.hit", true))
+		s.revalidate(ctx, problemID)
 	}
 
-	metaFile, err := os.ReadFile(metaPath)
+	// o job guarda a versão resolvida, não o symlink: uma troca ou remoção do problema enquanto ele
+	// espera na fila não muda o pacote que ele vai copiar
+	versionDir, err := filepath.EvalSymlinks(problemDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", fmt.Errorf("%w: %s is not in the cache", customErrors.ErrProblemNotFound, problemID)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve cached problem: %w", err)
+	}
+
+	metaFile, err := os.ReadFile(filepath.Join(versionDir, "meta.json"))
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to read meta.json: %w", err)
 	}
@@ -51,41 +116,138 @@ func (s *CacheService) GetProblemData(problemID string) ([]models.LanguageLimits
 		return nil, "", fmt.Errorf("corrupted meta.json: %w", err)
 	}
 
-	return limits, problemDir, nil
+	return limits, versionDir, nil
+}
+
+// revalidate confere com a API se o pacote em cache ainda é o atual, no máximo uma vez por
+// REVALIDATEINTERVAL para cada problema. A conferência roda em segundo plano: quem consultou usa
+// a cópia em cache, e a versão nova (se houver) vale para as próximas consultas. Falhas só vão
+// para o log.
+func (s *CacheService) revalidate(ctx context.Context, problemID string) {
+	if s.cacheConfig.ONLYLOCAL || s.cacheConfig.REVALIDATEINTERVAL <= 0 {
+		return
+	}
+
+	manifest, err := s.readManifest(problemID)
+	if err != nil {
+		s.logger.Warn("Manifesto do pacote ilegível, revalidando sem ele", logger.KeyProblemID, problemID, logger.Err(err))
+		manifest = packageManifest{}
+	}
+	if manifest.Source == packageSourceUpload || time.Since(manifest.CheckedAt) < s.cacheConfig.REVALIDATEINTERVAL {
+		return
+	}
+
+	// consultas simultâneas ao mesmo problema usam a cópia atual enquanto uma delas revalida
+	s.revalidatingMu.Lock()
+	if s.revalidating[problemID] {
+		s.revalidatingMu.Unlock()
+		return
+	}
+	s.revalidating[problemID] = true
+	s.revalidatingMu.Unlock()
+
+	// a conferência não termina com a requisição que a disparou; o limite vem de APITIMEOUT
+	go s.refreshPackage(context.WithoutCancel(ctx), problemID, manifest)
+}
+
+func (s *CacheService) refreshPackage(ctx context.Context, problemID string, manifest packageManifest) {
+	defer func() {
+		s.revalidatingMu.Lock()
+		delete(s.revalidating, problemID)
+		s.revalidatingMu.Unlock()
+	}()
+
+	updated, err := s.downloadPackage(ctx, problemID, manifest)
+	switch {
+	case err != nil:
+		metrics.CacheRevalidations.WithLabelValues(metrics.RevalidationError).Inc()
+		s.logger.Warn("Falha ao revalidar o pacote do problema, usando a cópia em cache", logger.KeyProblemID, problemID, logger.Err(err))
+		// a próxima tentativa espera o intervalo, para uma API fora do ar não atrasar toda consulta
+		if err := s.touchManifest(problemID, "", ""); err != nil {
+			s.logger.Warn("Falha ao gravar o manifesto do pacote", logger.KeyProblemID, problemID, logger.Err(err))
+		}
+	case updated:
+		metrics.CacheRevalidations.WithLabelValues(metrics.RevalidationUpdated).Inc()
+		s.logger.Info("Pacote do problema atualizado pela API", logger.KeyProblemID, problemID)
+	default:
+		metrics.CacheRevalidations.WithLabelValues(metrics.RevalidationNotModified).Inc()
+	}
 }
 
-func (s *CacheService) downloadAndExtract(problemID string, problemDir string) error {
-	fmt.Printf("missing cache, downloading data of %s...\n", problemID)
+// downloadPackage baixa o pacote da API e o instala. Com o manifesto da versão em cache, a
+// requisição é condicional (If-None-Match/If-Modified-Since); um 304 ou um .zip com o mesmo hash
+// só renovam o manifesto, e updated fica false.
+func (s *CacheService) downloadPackage(ctx context.Context, problemID string, current packageManifest) (updated bool, err error) {
+	ctx, span := tracing.Start(ctx, "cache.download", trace.WithAttributes(attribute.Bool("cache.conditional", current.ETag != "" || current.LastModified != "")))
+	defer func() { tracing.End(span, err) }()
+
+	apiURL := fmt.Sprintf("%s/%s/package", s.cacheConfig.APIURL, url.PathEscape(problemID))
 
-	apiURL := fmt.Sprintf("%s/%s/package", s.cacheConfig.APIURL, problemID)
+	// uma API travada não pode segurar a submissão (ou a revalidação) indefinidamente
+	ctx, cancel := context.WithTimeout(ctx, s.cacheConfig.APITIMEOUT)
+	defer cancel()
 
-	req, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
-		return err
+		return false, err
 	}
 	req.Header.Set("X-Admin-Token", s.cacheConfig.APIKEY)
+	if current.ETag != "" {
+		req.Header.Set("If-None-Match", current.ETag)
+	}
+	if current.LastModified != "" {
+		req.Header.Set("If-Modified-Since", current.LastModified)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return false, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return false, s.touchManifest(problemID, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"))
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return false, fmt.Errorf("%w: API returned status %s", customErrors.ErrProblemNotFound, resp.Status)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API returned status %s", resp.Status)
+		return false, fmt.Errorf("API returned status %s", resp.Status)
 	}
 
 	tmpZip, err := os.CreateTemp("", "problem-*.zip")
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return false, fmt.Errorf("failed to create temp file: %w", err)
 	}
 	defer os.Remove(tmpZip.Name())
 
-	_, err = io.Copy(tmpZip, resp.Body)
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tmpZip, hash), io.LimitReader(resp.Body, s.cacheConfig.MAXPACKAGEBYTES+1))
+	tmpZip.Close()
 	if err != nil {
-		return fmt.Errorf("download failed: %w", err)
+		return false, fmt.Errorf("download failed: %w", err)
+	}
+	if written > s.cacheConfig.MAXPACKAGEBYTES {
+		return false, fmt.Errorf("%w: API package exceeds %d bytes", customErrors.ErrInvalidPackage, s.cacheConfig.MAXPACKAGEBYTES)
 	}
-	tmpZip.Close()
 
-	return folderutils.Unzip(tmpZip.Name(), problemDir)
+	origin := packageOrigin{
+		Source:       packageSourceDownload,
+		SHA256:       hex.EncodeToString(hash.Sum(nil)),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	// APIs sem validadores HTTP devolvem sempre o .zip inteiro; o hash evita reinstalar o mesmo pacote
+	if current.SHA256 != "" && current.SHA256 == origin.SHA256 {
+		return false, s.touchManifest(problemID, origin.ETag, origin.LastModified)
+	}
+
+	if _, _, err := s.installPackage(problemID, tmpZip.Name(), origin); err != nil {
+		// para quem submete, um pacote grande demais na API é um pacote inválido, não um corpo grande demais
+		if errors.Is(err, customErrors.ErrPackageTooLarge) {
+			return false, fmt.Errorf("%w: %v", customErrors.ErrInvalidPackage, err)
+		}
+		return false, err
+	}
+	return true, nil
 }
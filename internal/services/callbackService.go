@@ -0,0 +1,252 @@
+package services
+
+import (
+	"IFJudger/internal/models"
+	"IFJudger/internal/models/configs"
+	"IFJudger/internal/repository"
+	"IFJudger/pkg/logger"
+	"IFJudger/pkg/metrics"
+	"IFJudger/pkg/tracing"
+	"IFJudger/pkg/webhook"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CallbackService entrega os resultados ao backend a partir do outbox persistido em SQLite.
+// Falhas são reagendadas com backoff exponencial até CallbackServiceConfig.MaxAttempts.
+type CallbackService struct {
+	repository *repository.CallbackRepository
+	config     configs.CallbackServiceConfig
+	client     *http.Client
+	logger     *slog.Logger
+
+	wakeup chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+const (
+	callbackPollInterval = 5 * time.Second
+	callbackBatchSize    = 20
+)
+
+func StartCallbackService(config configs.CallbackServiceConfig, repository *repository.CallbackRepository) (*CallbackService, error) {
+	if config.MaxAttempts <= 0 {
+		return nil, fmt.Errorf("callback max attempts must be positive")
+	}
+
+	// o transporte do otelhttp cria o span da requisição e propaga o contexto de trace nos headers
+	client := &http.Client{
+		Timeout:   config.RequestTimeout,
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	service := &CallbackService{
+		repository: repository,
+		config:     config,
+		client:     client,
+		logger:     logger.Component("callback"),
+		wakeup:     make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	if config.Secret == "" {
+		service.logger.Warn("CALLBACK_SECRET não definido, callbacks serão enviados sem assinatura.")
+	}
+
+	go service.dispatchLoop()
+
+	return service, nil
+}
+
+// Enqueue grava o evento no outbox; a entrega acontece em segundo plano.
+// Se webhookURL for vazio, o evento vai para o CallbackUrl padrão.
+func (s *CallbackService) Enqueue(ctx context.Context, event models.CallbackEvent, webhookURL string) {
+	if event.Status == models.StatusProcessing && !s.config.ProcessingEvents {
+		return
+	}
+
+	ctx, span := tracing.Start(ctx, "db.enqueue_callback", trace.WithAttributes(attribute.String("callback.event", event.Event)))
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	jsonData, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Erro ao serializar evento", logger.KeyJobID, event.SubmissionID, logger.Err(err))
+		return
+	}
+
+	target := s.config.CallbackUrl
+	if webhookURL != "" {
+		target = webhookURL
+	}
+
+	traceContext, err := json.Marshal(tracing.Inject(ctx))
+	if err != nil {
+		return
+	}
+
+	if err = s.repository.Enqueue(event.SubmissionID, target, string(jsonData), string(traceContext)); err != nil {
+		s.logger.Error("Falha ao gravar callback no outbox", logger.KeyJobID, event.SubmissionID, "event", event.Event, logger.Err(err))
+		return
+	}
+	s.logger.Debug("Callback enfileirado", logger.KeyJobID, event.SubmissionID, "event", event.Event)
+
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (s *CallbackService) ListDeliveries(status string, limit int) ([]models.CallbackDelivery, error) {
+	return s.repository.List(status, limit)
+}
+
+// Retry agenda um callback falho para ser entregue novamente, com as tentativas zeradas.
+func (s *CallbackService) Retry(id int64) error {
+	if err := s.repository.Retrigger(id); err != nil {
+		return err
+	}
+
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Shutdown interrompe o envio; callbacks pendentes continuam no outbox para a próxima inicialização.
+func (s *CallbackService) Shutdown(ctx context.Context) error {
+	s.once.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *CallbackService) dispatchLoop() {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		// o lease cobre uma tentativa de cada callback do lote, para que não sejam reenviados em paralelo
+		deliveries, err := s.repository.ClaimDue(callbackBatchSize, s.config.RequestTimeout*(callbackBatchSize+1))
+		if err != nil {
+			s.logger.Error("Falha ao buscar callbacks pendentes", logger.Err(err))
+		}
+
+		for _, delivery := range deliveries {
+			s.deliver(delivery)
+		}
+
+		if len(deliveries) == callbackBatchSize {
+			continue
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-s.wakeup:
+		case <-time.After(callbackPollInterval):
+		}
+	}
+}
+
+func (s *CallbackService) deliver(delivery models.CallbackDelivery) {
+	attempts := delivery.Attempts + 1
+	deliveryLog := s.logger.With(logger.KeyJobID, delivery.SubmissionID, "callback_id", delivery.ID, "attempt", attempts)
+
+	var carrier map[string]string
+	json.Unmarshal([]byte(delivery.TraceContext), &carrier)
+	ctx, span := tracing.Start(tracing.Extract(context.Background(), carrier), "callback.deliver", trace.WithAttributes(
+		attribute.String(logger.KeyJobID, delivery.SubmissionID),
+		attribute.Int64("callback.id", delivery.ID),
+		attribute.Int("callback.attempt", attempts),
+	))
+
+	err := s.post(ctx, delivery)
+	tracing.End(span, err)
+	if err == nil {
+		deliveryLog.Debug("Callback entregue")
+		if err := s.repository.MarkDelivered(delivery.ID, attempts); err != nil {
+			deliveryLog.Error("Falha ao marcar callback como entregue", logger.Err(err))
+		}
+		return
+	}
+
+	if attempts >= s.config.MaxAttempts {
+		metrics.CallbackFailures.WithLabelValues("failed").Inc()
+		deliveryLog.Error("Desistindo do callback após esgotar as tentativas", logger.Err(err))
+		if err := s.repository.MarkFailed(delivery.ID, attempts, err.Error()); err != nil {
+			deliveryLog.Error("Falha ao marcar callback como falho", logger.Err(err))
+		}
+		return
+	}
+
+	metrics.CallbackFailures.WithLabelValues("retry").Inc()
+	nextAttempt := time.Now().Add(s.backoff(attempts))
+	deliveryLog.Warn("Falha ao enviar callback, nova tentativa agendada", "next_attempt_at", nextAttempt, logger.Err(err))
+	if err := s.repository.MarkRetry(delivery.ID, attempts, nextAttempt, err.Error()); err != nil {
+		deliveryLog.Error("Falha ao reagendar callback", logger.Err(err))
+	}
+}
+
+// backoff dobra a espera a cada tentativa, limitada por MaxBackoff.
+func (s *CallbackService) backoff(attempts int) time.Duration {
+	wait := s.config.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= s.config.MaxBackoff {
+			return s.config.MaxBackoff
+		}
+	}
+	return wait
+}
+
+func (s *CallbackService) post(ctx context.Context, delivery models.CallbackDelivery) error {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid callback request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// assinado a cada tentativa, para que o timestamp reflita o envio e não a criação do callback
+	if s.config.Secret != "" {
+		webhook.SignRequest(req, []byte(s.config.Secret), body)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned status %s", resp.Status)
+	}
+	return nil
+}
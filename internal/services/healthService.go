@@ -0,0 +1,180 @@
+package services
+
+import (
+	"IFJudger/internal/models"
+	"IFJudger/internal/models/configs"
+	"IFJudger/pkg/logger"
+	"IFJudger/pkg/worker"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Nomes dos componentes verificados no readiness.
+const (
+	componentDatabase = "sqlite"
+	componentDocker   = "docker"
+	componentImages   = "images"
+	componentCache    = "cache_directory"
+	componentRunner   = "runner_binary"
+	componentQueue    = "queue"
+)
+
+type HealthService struct {
+	db            *sql.DB
+	workerService *WorkerService
+	docker        *worker.DockerProbe
+	config        configs.HealthServiceConfig
+}
+
+func StartHealthService(config configs.HealthServiceConfig, db *sql.DB, workerService *WorkerService) (*HealthService, error) {
+	service := &HealthService{
+		db:            db,
+		workerService: workerService,
+		config:        config,
+	}
+
+	var err error
+	service.docker, err = worker.NewDockerProbe()
+	if err != nil {
+		logger.Component("health").Warn("Falha ao criar cliente do Docker, o readiness vai reportar o Docker como indisponível", logger.Err(err))
+	}
+
+	return service, nil
+}
+
+// Readiness verifica em paralelo todas as dependências necessárias para avaliar submissões.
+// A instância só está pronta se todos os componentes estiverem ok.
+func (s *HealthService) Readiness(ctx context.Context) models.ReadinessReport {
+	checks := []struct {
+		name  string
+		check func(context.Context) error
+	}{
+		{componentDatabase, s.checkDatabase},
+		{componentDocker, s.checkDocker},
+		{componentImages, s.checkImages},
+		{componentCache, s.checkCacheDirectory},
+		{componentRunner, s.checkRunner},
+		{componentQueue, s.checkQueue},
+	}
+
+	report := models.ReadinessReport{
+		Ready:      true,
+		Components: make([]models.ComponentHealth, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, s.config.CheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.check(checkCtx)
+
+			component := models.ComponentHealth{
+				Name:      c.name,
+				Status:    models.HealthOK,
+				LatencyMS: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				component.Status = models.HealthFail
+				component.Message = err.Error()
+			}
+			report.Components[i] = component
+		}()
+	}
+	wg.Wait()
+
+	for _, component := range report.Components {
+		if component.Status != models.HealthOK {
+			report.Ready = false
+		}
+	}
+
+	return report
+}
+
+func (s *HealthService) checkDatabase(ctx context.Context) error {
+	var one int
+	if err := s.db.QueryRowContext(ctx, `SELECT 1`).Scan(&one); err != nil {
+		return fmt.Errorf("database unreachable: %w", err)
+	}
+	return nil
+}
+
+func (s *HealthService) checkDocker(ctx context.Context) error {
+	if s.docker == nil {
+		return fmt.Errorf("docker client not initialized")
+	}
+	if err := s.docker.Ping(ctx); err != nil {
+		return fmt.Errorf("docker daemon unreachable: %w", err)
+	}
+	return nil
+}
+
+func (s *HealthService) checkImages(ctx context.Context) error {
+	if s.docker == nil {
+		return fmt.Errorf("docker client not initialized")
+	}
+	images := make([]string, len(models.Languages))
+	for i, language := range models.Languages {
+		images[i] = language.Image
+	}
+	missing, err := s.docker.MissingImages(ctx, images)
+	if err != nil {
+		return fmt.Errorf("failed to inspect images: %w", err)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing images: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkCacheDirectory garante que é possível gravar no cache, criando e removendo um arquivo temporário
+func (s *HealthService) checkCacheDirectory(ctx context.Context) error {
+	file, err := os.CreateTemp(s.config.CacheDirectory, ".readyz-*")
+	if err != nil {
+		return fmt.Errorf("cache directory not writable: %w", err)
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+func (s *HealthService) checkRunner(ctx context.Context) error {
+	info, err := os.Stat(s.config.RunnerPath)
+	if err != nil {
+		return fmt.Errorf("runner binary not found: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("runner binary %s is not a regular file", s.config.RunnerPath)
+	}
+	return nil
+}
+
+// checkQueue reprova a instância quando ela está desligando ou quando a fila passa do limite de saturação
+func (s *HealthService) checkQueue(ctx context.Context) error {
+	if s.workerService.IsDraining() {
+		return fmt.Errorf("shutting down")
+	}
+
+	queued, err := s.workerService.QueueDepth()
+	if err != nil {
+		return fmt.Errorf("failed to count queued jobs: %w", err)
+	}
+
+	if s.config.QueueSize > 0 {
+		threshold := max(1, s.config.QueueSize*s.config.QueueSaturationPercent/100)
+		if queued >= threshold {
+			return fmt.Errorf("queue saturated: %d/%d jobs queued", queued, s.config.QueueSize)
+		}
+	}
+	return nil
+}
@@ -3,68 +3,341 @@ package services
 import (
 	"IFJudger/internal/api/dto"
 	"IFJudger/internal/models"
+	"IFJudger/internal/models/configs"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/pkg/logger"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
 	"time"
 )
 
 type JudgerService struct {
 	workerService *WorkerService
 	cacheService  *CacheService
+	config        configs.JudgerServiceConfig
+	logger        *slog.Logger
 }
 
-func StartJudgerService(workerService *WorkerService, cacheService *CacheService) (*JudgerService, error) {
+func StartJudgerService(config configs.JudgerServiceConfig, workerService *WorkerService, cacheService *CacheService) (*JudgerService, error) {
 	return &JudgerService{
 		workerService: workerService,
 		cacheService:  cacheService,
+		config:        config,
+		logger:        logger.Component("judger_service"),
 	}, nil
 }
 
-func (s *JudgerService) EnqueueJudge(judgeRequest dto.JudgeRequest) (string, error) {
-	limits, path, err := s.cacheService.GetProblemData(judgeRequest.ProblemID)
+// EnqueueJudge valida a submissão e a coloca na fila. replayed indica que a Idempotency-Key
+// já tinha sido usada e o id devolvido é o da submissão original.
+func (s *JudgerService) EnqueueJudge(ctx context.Context, judgeRequest dto.JudgeRequest) (id string, replayed bool, err error) {
+	job, err := s.buildJudgeJob(ctx, judgeRequest, s.cacheService.GetProblemData)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	limit, err := FindLimitToken(judgeRequest.LanguageToken, &limits)
+	id, replayed, err = s.workerService.EnqueueJob(ctx, job, judgeRequest.ClientQuota)
 	if err != nil {
-		return "", err
+		return id, false, err
+	}
+	s.logger.Debug("Submissão aceita", logger.KeyJobID, id, logger.KeyProblemID, job.ProblemID, "replayed", replayed, "time_limit", job.TimeLimit.String(), "memory_mb", job.MaximumRamMB)
+	return id, replayed, nil
+}
+
+// EnqueueBatch valida todas as submissões do lote e as coloca na fila juntas: se alguma for
+// inválida, nenhuma é enfileirada e o erro é um *customErrors.BatchError com o motivo de cada uma.
+// Os tokens devolvidos seguem a ordem de batchRequest.Submissions.
+func (s *JudgerService) EnqueueBatch(ctx context.Context, batchRequest dto.BatchJudgeRequest) (string, []string, error) {
+	if len(batchRequest.Submissions) == 0 {
+		return "", nil, fmt.Errorf("%w: batch has no submissions", customErrors.ErrValidation)
+	}
+	if len(batchRequest.Submissions) > s.config.BatchMaxSize {
+		return "", nil, fmt.Errorf("%w: batch has %d submissions, the limit is %d", customErrors.ErrValidation, len(batchRequest.Submissions), s.config.BatchMaxSize)
+	}
+
+	// lotes costumam repetir o mesmo problema; cada um é buscado no cache uma vez só
+	type problemData struct {
+		limits []models.LanguageLimits
+		path   string
+		err    error
+	}
+	problems := make(map[string]problemData)
+	getProblemData := func(ctx context.Context, problemID string) ([]models.LanguageLimits, string, error) {
+		data, ok := problems[problemID]
+		if !ok {
+			data.limits, data.path, data.err = s.cacheService.GetProblemData(ctx, problemID)
+			problems[problemID] = data
+		}
+		return data.limits, data.path, data.err
+	}
+
+	jobs := make([]models.Job, len(batchRequest.Submissions))
+	var batchErr customErrors.BatchError
+	for i, judgeRequest := range batchRequest.Submissions {
+		judgeRequest.ClientID = batchRequest.ClientID
+
+		job, err := s.buildJudgeJob(ctx, judgeRequest, getProblemData)
+		if err != nil {
+			if !isSubmissionError(err) {
+				return "", nil, err
+			}
+			batchErr.Items = append(batchErr.Items, customErrors.BatchItemError{Index: i, Err: err})
+			continue
+		}
+		jobs[i] = job
+	}
+	if len(batchErr.Items) > 0 {
+		return "", nil, &batchErr
+	}
+
+	batchID, ids, err := s.workerService.EnqueueBatch(ctx, batchRequest.ClientID, jobs, batchRequest.ClientQuota)
+	if err != nil {
+		return "", nil, err
+	}
+	s.logger.Debug("Lote aceito", "batch_id", batchID, "submissions", len(ids), "problems", len(problems))
+	return batchID, ids, nil
+}
+
+// isSubmissionError indica se o erro é um problema da própria submissão, e não uma falha
+// do judger ao validá-la (por exemplo, ao baixar o problema).
+func isSubmissionError(err error) bool {
+	return errors.Is(err, customErrors.ErrValidation) ||
+		errors.Is(err, customErrors.ErrInvalidLanguage) ||
+		errors.Is(err, customErrors.ErrInvalidWebhookURL) ||
+		errors.Is(err, customErrors.ErrProblemNotFound)
+}
+
+// buildJudgeJob valida a submissão e monta o job com os limites do problema, obtidos por getProblemData.
+func (s *JudgerService) buildJudgeJob(ctx context.Context, judgeRequest dto.JudgeRequest, getProblemData func(context.Context, string) ([]models.LanguageLimits, string, error)) (models.Job, error) {
+	priority, err := models.ParsePriority(judgeRequest.Priority)
+	if err != nil {
+		return models.Job{}, fmt.Errorf("%w: %v", customErrors.ErrValidation, err)
+	}
+	if priority == models.PriorityRun {
+		return models.Job{}, fmt.Errorf("%w: invalid priority %q for submissions", customErrors.ErrValidation, priority)
+	}
+
+	if !problemIDPattern.MatchString(judgeRequest.ProblemID) {
+		return models.Job{}, fmt.Errorf("%w: invalid problem id", customErrors.ErrValidation)
+	}
+
+	if err := s.validateWebhookURL(judgeRequest.WebhookURL); err != nil {
+		return models.Job{}, err
 	}
 
 	token, err := LanguageTokenToID(judgeRequest.LanguageToken)
 	if err != nil {
-		return "", err
+		return models.Job{}, err
+	}
+
+	requestLog := s.logger.With(logger.KeyProblemID, judgeRequest.ProblemID, "language", judgeRequest.LanguageToken)
+
+	limits, path, err := getProblemData(ctx, judgeRequest.ProblemID)
+	if err != nil {
+		requestLog.Warn("Falha ao obter dados do problema", logger.Err(err))
+		return models.Job{}, err
+	}
+
+	limit, err := FindLimitToken(judgeRequest.LanguageToken, &limits)
+	if err != nil {
+		requestLog.Warn("Linguagem sem limites definidos no problema", logger.Err(err))
+		return models.Job{}, err
 	}
 
 	job := models.Job{
+		ProblemID:    judgeRequest.ProblemID,
 		LanguageID:   token,
 		CachePath:    path,
 		TimeLimit:    time.Duration(limit.TimeLimitSeconds * int(time.Second)),
 		MaximumRamMB: limit.MaximumRamMB,
 		Code:         judgeRequest.Code,
+		Priority:     priority,
+		WebhookURL:   judgeRequest.WebhookURL,
+		Kind:         models.JobKindJudge,
+		ClientID:     judgeRequest.ClientID,
+	}
+	if judgeRequest.IdempotencyKey != "" {
+		job.IdempotencyKey = judgeRequest.IdempotencyKey
+		job.IdempotencyHash = fingerprintSubmission(judgeRequest, priority)
+	}
+	return job, nil
+}
+
+// fingerprintSubmission resume o conteúdo da submissão, para reconhecer uma Idempotency-Key
+// repetida com outra requisição. A prioridade entra já normalizada, então omiti-la equivale a "practice".
+func fingerprintSubmission(req dto.JudgeRequest, priority models.Priority) string {
+	hash := sha256.New()
+	for _, field := range []string{req.ProblemID, req.LanguageToken, string(priority), req.WebhookURL, req.Code} {
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// EnqueueRun enfileira uma execução do código com a entrada do usuário, na lane run,
+// usando os limites padrão do modo run em vez dos limites de um problema.
+func (s *JudgerService) EnqueueRun(ctx context.Context, runRequest dto.RunRequest) (string, error) {
+	if err := s.validateWebhookURL(runRequest.WebhookURL); err != nil {
+		return "", err
 	}
 
-	id, err := s.workerService.EnqueueJob(job)
+	languageID, err := LanguageTokenToID(runRequest.LanguageToken)
 	if err != nil {
-		return id, err
+		return "", err
 	}
-	return id, nil
+
+	job := models.Job{
+		LanguageID:       languageID,
+		TimeLimit:        s.config.RunTimeLimit,
+		MaximumRamMB:     s.config.RunMemoryLimitMB,
+		Code:             runRequest.Code,
+		WebhookURL:       runRequest.WebhookURL,
+		Priority:         models.PriorityRun,
+		Kind:             models.JobKindRun,
+		Input:            runRequest.Input,
+		OutputLimitBytes: s.config.RunOutputLimitBytes,
+		ClientID:         runRequest.ClientID,
+	}
+
+	id, _, err := s.workerService.EnqueueJob(ctx, job, runRequest.ClientQuota)
+	return id, err
 }
 
+// GetResult devolve o estado da submissão, ou customErrors.ErrNotFound se ela não existir.
 func (s *JudgerService) GetResult(token string) (models.JobResult, error) {
-	result, exists := s.workerService.GetResult(token)
-	if exists == false {
-		return models.JobResult{}, fmt.Errorf("job does not exist")
-	}
+	return s.workerService.GetResult(token)
+}
 
+// GetRun devolve o estado de uma execução do modo run. Submissões corrigidas (/submit) não são
+// execuções: para elas, como para ids inexistentes, retorna customErrors.ErrNotFound.
+func (s *JudgerService) GetRun(token string) (models.JobResult, error) {
+	result, err := s.workerService.GetResult(token)
+	if err != nil {
+		return models.JobResult{}, err
+	}
+	if result.Kind != models.JobKindRun {
+		return models.JobResult{}, customErrors.ErrNotFound
+	}
 	return result, nil
 }
 
+// Cancel cancela uma submissão que ainda está na fila. Com clientID preenchido, só as submissões
+// desse cliente podem ser canceladas; vazio (autenticação desligada ou chave admin) cancela qualquer uma.
+// Retorna customErrors.ErrNotFound se ela não existir ou for de outro cliente e
+// customErrors.ErrNotCancelable se já estiver em execução ou tiver terminado.
+func (s *JudgerService) Cancel(ctx context.Context, token, clientID string) (models.JobResult, error) {
+	return s.workerService.CancelJob(ctx, token, clientID)
+}
+
+// ListLanguages devolve as linguagens aceitas nas submissões.
+func (s *JudgerService) ListLanguages() []models.Language {
+	return models.Languages
+}
+
+// ProblemLanguage é uma linguagem aceita pelo problema, com os limites definidos no meta.json dele.
+type ProblemLanguage struct {
+	models.Language
+	Limits models.LanguageLimits
+}
+
+// ListProblemLanguages lê o meta.json do problema (baixando o pacote se ainda não estiver no cache)
+// e devolve as linguagens que ele permite e que o judger suporta, na ordem do meta.json.
+func (s *JudgerService) ListProblemLanguages(ctx context.Context, problemID string) ([]ProblemLanguage, error) {
+	limits, _, err := s.cacheService.GetProblemData(ctx, problemID)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make([]ProblemLanguage, 0, len(limits))
+	for _, limit := range limits {
+		language, ok := models.LanguageByToken(limit.Name)
+		if !ok {
+			s.logger.Debug("Linguagem do meta.json não suportada, ignorando", logger.KeyProblemID, problemID, "language", limit.Name)
+			continue
+		}
+		languages = append(languages, ProblemLanguage{Language: language, Limits: limit})
+	}
+	return languages, nil
+}
+
+// GetBatch devolve o progresso do lote, ou customErrors.ErrNotFound se ele não existir.
+func (s *JudgerService) GetBatch(id string) (models.BatchStatus, error) {
+	return s.workerService.GetBatch(id)
+}
+
+// validateWebhookURL aceita apenas URLs http(s) cujo host esteja em AllowedWebhookHosts.
+// Uma entrada do allowlist pode ser só o host ("aquilles.run") ou host e porta ("localhost:4040").
+func (s *JudgerService) validateWebhookURL(rawURL string) error {
+	if rawURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: must be an absolute http(s) URL", customErrors.ErrInvalidWebhookURL)
+	}
+
+	for _, allowed := range s.config.AllowedWebhookHosts {
+		if strings.EqualFold(allowed, u.Host) || strings.EqualFold(allowed, u.Hostname()) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: host %s is not allowed", customErrors.ErrInvalidWebhookURL, u.Host)
+}
+
+// WatchResult devolve o estado atual da submissão e um canal com os próximos eventos de progresso.
+// A inscrição é feita antes da leitura do estado para que nenhuma transição seja perdida entre os dois.
+func (s *JudgerService) WatchResult(token string) (models.JobResult, <-chan models.ProgressEvent, func(), error) {
+	events, unsubscribe := s.workerService.Subscribe(token)
+
+	result, err := s.workerService.GetResult(token)
+	if err != nil {
+		unsubscribe()
+		return models.JobResult{}, nil, nil, err
+	}
+
+	return result, events, unsubscribe, nil
+}
+
+// ListSubmissions valida os filtros de busca e devolve uma página de submissões.
+func (s *JudgerService) ListSubmissions(filter models.SubmissionFilter) (models.SubmissionPage, error) {
+	switch filter.Status {
+	case "", models.StatusQueued, models.StatusProcessing, models.StatusSuccess, models.StatusError:
+	default:
+		return models.SubmissionPage{}, fmt.Errorf("%w: invalid status %q", customErrors.ErrInvalidFilter, filter.Status)
+	}
+
+	if filter.Language != "" {
+		if _, err := LanguageTokenToID(filter.Language); err != nil {
+			return models.SubmissionPage{}, fmt.Errorf("%w: %v", customErrors.ErrInvalidFilter, err)
+		}
+	}
+
+	if filter.Priority != "" {
+		if _, err := models.ParsePriority(filter.Priority); err != nil {
+			return models.SubmissionPage{}, fmt.Errorf("%w: %v", customErrors.ErrInvalidFilter, err)
+		}
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return models.SubmissionPage{}, fmt.Errorf("%w: 'from' must be before 'to'", customErrors.ErrInvalidFilter)
+	}
+
+	return s.workerService.ListSubmissions(filter)
+}
+
 func LanguageTokenToID(token string) (models.LanguageID, error) {
-	if token == "python" {
-		return models.Python, nil
-	} else {
-		return 0, fmt.Errorf("invalid language")
+	language, ok := models.LanguageByToken(token)
+	if !ok {
+		return 0, fmt.Errorf("%w %q", customErrors.ErrInvalidLanguage, token)
 	}
+	return language.ID, nil
 }
 
 func FindLimitToken(token string, limits *[]models.LanguageLimits) (*models.LanguageLimits, error) {
@@ -74,5 +347,5 @@ func FindLimitToken(token string, limits *[]models.LanguageLimits) (*models.Lang
 		}
 	}
 
-	return nil, fmt.Errorf("limit not found for token %s in problem", token)
+	return nil, fmt.Errorf("%w: problem has no limits for %q", customErrors.ErrInvalidLanguage, token)
 }
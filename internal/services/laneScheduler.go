@@ -0,0 +1,68 @@
+package services
+
+import (
+	"IFJudger/internal/models"
+	"sort"
+	"sync"
+)
+
+// laneScheduler distribui os workers compartilhados entre as lanes de prioridade
+// usando smooth weighted round-robin: uma lane com peso 6 é servida seis vezes
+// para cada vez de uma lane com peso 1, sem que a de menor peso fique parada.
+type laneScheduler struct {
+	mu      sync.Mutex
+	lanes   []models.Priority
+	weights map[models.Priority]int
+	current map[models.Priority]int
+	total   int
+}
+
+func newLaneScheduler(weights map[models.Priority]int) *laneScheduler {
+	scheduler := &laneScheduler{
+		weights: weights,
+		current: make(map[models.Priority]int),
+	}
+
+	for _, lane := range models.Priorities {
+		if weights[lane] > 0 {
+			scheduler.lanes = append(scheduler.lanes, lane)
+			scheduler.total += weights[lane]
+		}
+	}
+
+	return scheduler
+}
+
+// next retorna as lanes na ordem em que o worker deve tentar reservar um job:
+// primeiro a lane escolhida pelo round-robin, depois as demais por peso,
+// para que nenhum worker fique ocioso enquanto houver trabalho em alguma lane.
+func (ls *laneScheduler) next() []models.Priority {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if len(ls.lanes) == 0 {
+		return nil
+	}
+
+	var selected models.Priority
+	for _, lane := range ls.lanes {
+		ls.current[lane] += ls.weights[lane]
+		if selected == "" || ls.current[lane] > ls.current[selected] {
+			selected = lane
+		}
+	}
+	ls.current[selected] -= ls.total
+
+	order := []models.Priority{selected}
+	rest := make([]models.Priority, 0, len(ls.lanes)-1)
+	for _, lane := range ls.lanes {
+		if lane != selected {
+			rest = append(rest, lane)
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		return ls.weights[rest[i]] > ls.weights[rest[j]]
+	})
+
+	return append(order, rest...)
+}
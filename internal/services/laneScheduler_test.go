@@ -0,0 +1,159 @@
+package services
+
+import (
+	"IFJudger/internal/models"
+	"IFJudger/internal/models/configs"
+	"IFJudger/internal/repository"
+	"IFJudger/pkg/logger"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestLaneSchedulerWeights(t *testing.T) {
+	weights := map[models.Priority]int{
+		models.PriorityContest:  6,
+		models.PriorityPractice: 3,
+		models.PriorityRun:      1,
+	}
+	scheduler := newLaneScheduler(weights)
+
+	// em cada ciclo de 10 escolhas, cada lane vem primeiro tantas vezes quanto o seu peso
+	for cycle := 0; cycle < 3; cycle++ {
+		first := map[models.Priority]int{}
+		for i := 0; i < 10; i++ {
+			order := scheduler.next()
+			first[order[0]]++
+
+			// as demais lanes vêm depois, da mais pesada para a mais leve; a lane sem peso não aparece
+			if len(order) != 3 || slices.Contains(order, models.PriorityRejudge) {
+				t.Fatalf("ordem = %v, esperadas as 3 lanes com peso", order)
+			}
+			if weights[order[1]] < weights[order[2]] {
+				t.Errorf("ordem = %v: as lanes restantes deveriam vir por peso", order)
+			}
+		}
+
+		for lane, count := range weights {
+			if first[lane] != count {
+				t.Errorf("ciclo %d: %s escolhida %d vezes, esperado %d", cycle, lane, first[lane], count)
+			}
+		}
+	}
+}
+
+func TestLaneSchedulerSmooth(t *testing.T) {
+	scheduler := newLaneScheduler(map[models.Priority]int{
+		models.PriorityContest:  6,
+		models.PriorityPractice: 1,
+	})
+
+	// a lane leve é servida no meio do ciclo, não depois de todas as escolhas da pesada
+	var picks []models.Priority
+	for i := 0; i < 7; i++ {
+		picks = append(picks, scheduler.next()[0])
+	}
+	index := slices.Index(picks, models.PriorityPractice)
+	if index < 1 || index > 5 {
+		t.Errorf("escolhas = %v: practice deveria aparecer no meio do ciclo", picks)
+	}
+}
+
+func TestBuildLanes(t *testing.T) {
+	weights, reserved, err := buildLanes(configs.WorkerServiceConfig{
+		MaxWorkers:          4,
+		LaneWeights:         map[string]int{"contest": 5},
+		LaneReservedWorkers: map[string]int{"run": 1, "contest": 1},
+	})
+	if err != nil {
+		t.Fatalf("buildLanes: %v", err)
+	}
+
+	// as lanes sem peso configurado ficam com peso 1
+	if weights[models.PriorityContest] != 5 || weights[models.PriorityPractice] != 1 || weights[models.PriorityRejudge] != 1 {
+		t.Errorf("pesos = %v", weights)
+	}
+
+	// os primeiros workers são reservados, na ordem de models.Priorities; os demais são compartilhados
+	want := []models.Priority{models.PriorityContest, models.PriorityRun, "", ""}
+	if !slices.Equal(reserved, want) {
+		t.Errorf("workers reservados = %v, esperado %v", reserved, want)
+	}
+
+	invalid := []struct {
+		name   string
+		config configs.WorkerServiceConfig
+	}{
+		{name: "lane desconhecida", config: configs.WorkerServiceConfig{MaxWorkers: 2, LaneWeights: map[string]int{"urgente": 2}}},
+		{name: "peso zero", config: configs.WorkerServiceConfig{MaxWorkers: 2, LaneWeights: map[string]int{"contest": 0}}},
+		{name: "reserva negativa", config: configs.WorkerServiceConfig{MaxWorkers: 2, LaneReservedWorkers: map[string]int{"run": -1}}},
+		{name: "reservas acima de MaxWorkers", config: configs.WorkerServiceConfig{MaxWorkers: 2, LaneReservedWorkers: map[string]int{"run": 2, "contest": 1}}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := buildLanes(tt.config); err == nil {
+				t.Error("buildLanes aceitou a configuração")
+			}
+		})
+	}
+}
+
+// TestReservedWorkerClaimsOnlyItsLane confere que um worker reservado não pega jobs de outras
+// lanes, mesmo ocioso, enquanto os compartilhados pegam de qualquer uma.
+func TestReservedWorkerClaimsOnlyItsLane(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "judger.db"))
+	if err != nil {
+		t.Fatalf("abrir banco: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo, err := repository.StartSubmissionRepository(db)
+	if err != nil {
+		t.Fatalf("StartSubmissionRepository: %v", err)
+	}
+
+	config := configs.WorkerServiceConfig{
+		MaxWorkers:          2,
+		LeaseTimeout:        time.Minute,
+		LaneReservedWorkers: map[string]int{"run": 1},
+	}
+	weights, reservedLanes, err := buildLanes(config)
+	if err != nil {
+		t.Fatalf("buildLanes: %v", err)
+	}
+	service := &WorkerService{
+		repository:    repo,
+		config:        config,
+		instanceID:    "teste",
+		logger:        logger.Component("worker_service"),
+		scheduler:     newLaneScheduler(weights),
+		reservedLanes: reservedLanes,
+	}
+
+	for _, job := range []models.Job{
+		{ID: "pratica", Priority: models.PriorityPractice},
+		{ID: "execucao", Priority: models.PriorityRun},
+	} {
+		if err := repo.CreateJob(job, 10, 0, time.Time{}); err != nil {
+			t.Fatalf("CreateJob(%s): %v", job.ID, err)
+		}
+	}
+
+	// o worker 0 é o reservado para run
+	job, found := service.claimJob(0)
+	if !found || job.ID != "execucao" {
+		t.Fatalf("worker reservado reservou %q (found %v), esperado execucao", job.ID, found)
+	}
+	if job, found := service.claimJob(0); found {
+		t.Errorf("worker reservado pegou %q de outra lane", job.ID)
+	}
+
+	job, found = service.claimJob(1)
+	if !found || job.ID != "pratica" {
+		t.Errorf("worker compartilhado reservou %q (found %v), esperado pratica", job.ID, found)
+	}
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	folderutils "IFJudger/pkg/folder_utils"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractPackage extrai o .zip do pacote em dir, parando no primeiro arquivo acima de maxFileBytes
+// (customErrors.PackageError com file_too_large) ou quando a soma passa de maxTotalBytes
+// (customErrors.ErrPackageTooLarge). Outras falhas do .zip viram customErrors.ErrInvalidPackage.
+func ExtractPackage(zipPath string, dir string, maxFileBytes int64, maxTotalBytes int64) error {
+	err := folderutils.Unzip(zipPath, dir, maxFileBytes, maxTotalBytes)
+	if err == nil {
+		return nil
+	}
+
+	var limitErr *folderutils.LimitError
+	switch {
+	case errors.As(err, &limitErr) && limitErr.Name != "":
+		var report models.PackageReport
+		addIssue(&report, models.SeverityError, models.IssueFileTooLarge, limitErr.Name,
+			fmt.Sprintf("%s has more than %d bytes", limitErr.Name, limitErr.Limit))
+		return &customErrors.PackageError{Issues: report.Issues}
+	case errors.As(err, &limitErr):
+		return fmt.Errorf("%w: extracted content limit is %d bytes", customErrors.ErrPackageTooLarge, limitErr.Limit)
+	default:
+		return fmt.Errorf("%w: %v", customErrors.ErrInvalidPackage, err)
+	}
+}
+
+// CheckPackageSize aplica a um pacote já extraído em dir os mesmos limites de ExtractPackage:
+// os arquivos acima de maxFileBytes viram um customErrors.PackageError com file_too_large, e uma
+// soma acima de maxTotalBytes, customErrors.ErrPackageTooLarge. Serve para validar diretórios,
+// que não passam pela extração.
+func CheckPackageSize(dir string, maxFileBytes int64, maxTotalBytes int64) error {
+	// WalkDir não segue um symlink na raiz, e o problema no cache é um symlink para a versão atual
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read package: %w", err)
+	}
+
+	var report models.PackageReport
+	var total int64
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		if info.Size() > maxFileBytes {
+			name, _ := filepath.Rel(root, path)
+			name = filepath.ToSlash(name)
+			addIssue(&report, models.SeverityError, models.IssueFileTooLarge, name,
+				fmt.Sprintf("%s has more than %d bytes", name, maxFileBytes))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read package: %w", err)
+	}
+
+	if len(report.Issues) > 0 {
+		return &customErrors.PackageError{Issues: report.Issues}
+	}
+	if total > maxTotalBytes {
+		return fmt.Errorf("%w: extracted content limit is %d bytes", customErrors.ErrPackageTooLarge, maxTotalBytes)
+	}
+	return nil
+}
+
+// ValidatePackage confere o pacote de problema extraído em dir: o meta.json, os pares de casos de
+// teste e as terminações de linha. É a mesma validação do download, do upload e do subcomando
+// validate; o tamanho dos arquivos é conferido à parte, por ExtractPackage ou CheckPackageSize. O
+// erro indica só falhas ao ler o diretório; os problemas do pacote ficam no relatório.
+func ValidatePackage(dir string) (models.PackageReport, error) {
+	var report models.PackageReport
+	report.Languages = validateMeta(dir, &report)
+	if err := validateFiles(dir, &report); err != nil {
+		return models.PackageReport{}, fmt.Errorf("failed to read package: %w", err)
+	}
+	return report, nil
+}
+
+func validateMeta(dir string, report *models.PackageReport) []models.LanguageLimits {
+	metaFile, err := os.ReadFile(filepath.Join(dir, "meta.json"))
+	if err != nil {
+		message := "meta.json is missing"
+		if !os.IsNotExist(err) {
+			message = fmt.Sprintf("meta.json could not be read: %v", err)
+		}
+		addIssue(report, models.SeverityError, models.IssueInvalidMeta, "meta.json", message)
+		return nil
+	}
+
+	var limits []models.LanguageLimits
+	if err := json.Unmarshal(metaFile, &limits); err != nil {
+		addIssue(report, models.SeverityError, models.IssueInvalidMeta, "meta.json", fmt.Sprintf("meta.json is not valid: %v", err))
+		return nil
+	}
+	if len(limits) == 0 {
+		addIssue(report, models.SeverityError, models.IssueNoLanguages, "meta.json", "meta.json has no languages")
+		return limits
+	}
+
+	seen := map[string]bool{}
+	for i, entry := range limits {
+		if seen[entry.Name] {
+			addIssue(report, models.SeverityError, models.IssueDuplicateLanguage, "meta.json",
+				fmt.Sprintf("entry %d repeats language %q", i, entry.Name))
+		}
+		seen[entry.Name] = true
+
+		if entry.TimeLimitSeconds <= 0 || entry.MaximumRamMB <= 0 {
+			addIssue(report, models.SeverityError, models.IssueInvalidLimit, "meta.json",
+				fmt.Sprintf("entry %d (%q) must have positive time_limit and memory_limit", i, entry.Name))
+		}
+
+		// linguagens desconhecidas não impedem a instalação: as submissões nelas é que são recusadas
+		if _, ok := models.LanguageByToken(entry.Name); !ok {
+			addIssue(report, models.SeverityWarning, models.IssueUnknownLanguage, "meta.json",
+				fmt.Sprintf("entry %d uses unsupported language %q", i, entry.Name))
+		}
+	}
+	return limits
+}
+
+// validateFiles confere os pares <id>.in/<id>.out da raiz, que são os que o runner executa.
+func validateFiles(dir string, report *models.PackageReport) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names[entry.Name()] = true
+		}
+	}
+
+	var crlfFiles, lfFiles int
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+
+		var counterpart string
+		switch filepath.Ext(name) {
+		case ".in":
+			counterpart = strings.TrimSuffix(name, ".in") + ".out"
+			if names[counterpart] {
+				report.TestCases++
+			}
+		case ".out":
+			counterpart = strings.TrimSuffix(name, ".out") + ".in"
+		default:
+			continue
+		}
+		if !names[counterpart] {
+			addIssue(report, models.SeverityError, models.IssueOrphanFile, name,
+				fmt.Sprintf("%s has no matching %s", name, counterpart))
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		crlf := bytes.Count(content, []byte("\r\n"))
+		lf := bytes.Count(content, []byte("\n")) - crlf
+		switch {
+		case crlf > 0 && lf > 0:
+			addIssue(report, models.SeverityWarning, models.IssueMixedLineEndings, name,
+				fmt.Sprintf("%s mixes CRLF and LF line endings", name))
+		case crlf > 0:
+			crlfFiles++
+		case lf > 0:
+			lfFiles++
+		}
+	}
+
+	if crlfFiles > 0 && lfFiles > 0 {
+		addIssue(report, models.SeverityWarning, models.IssueMixedLineEndings, "",
+			fmt.Sprintf("%d test files use CRLF line endings and %d use LF", crlfFiles, lfFiles))
+	}
+	if report.TestCases == 0 {
+		addIssue(report, models.SeverityError, models.IssueNoTestCases, "", "package has no test cases")
+	}
+	return nil
+}
+
+func addIssue(report *models.PackageReport, severity, code, file, message string) {
+	report.Issues = append(report.Issues, models.PackageIssue{
+		Severity: severity,
+		Code:     code,
+		File:     file,
+		Message:  message,
+	})
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"IFJudger/internal/models"
+	customErrors "IFJudger/internal/models/errors"
+	"IFJudger/pkg/logger"
+	"IFJudger/pkg/tracing"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// versionsDirectory guarda, dentro do CACHEDIRECTORY, as versões instaladas de cada problema.
+// O diretório <id><CACHEFILEEXTENSION> é um symlink para a versão atual.
+const versionsDirectory = ".versions"
+
+// manifestFile, em .versions/<id>/, descreve a versão atual e de onde ela veio.
+const manifestFile = "manifest.json"
+
+// Origens de um pacote instalado. Pacotes enviados por upload não são revalidados com a API.
+const (
+	packageSourceDownload = "download"
+	packageSourceUpload   = "upload"
+)
+
+var problemIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// packageOrigin identifica o .zip que originou uma versão: o hash do conteúdo e, nos downloads,
+// os validadores HTTP usados nas revalidações.
+type packageOrigin struct {
+	Source       string
+	SHA256       string
+	ETag         string
+	LastModified string
+}
+
+type packageManifest struct {
+	Version      string    `json:"version"`
+	Source       string    `json:"source"`
+	SHA256       string    `json:"sha256"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	InstalledAt  time.Time `json:"installed_at"`
+	CheckedAt    time.Time `json:"checked_at"`
+}
+
+// UploadPackage valida o .zip recebido e o instala como a nova versão do problema. O retorno
+// replaced indica se havia uma versão anterior no cache.
+func (s *CacheService) UploadPackage(ctx context.Context, problemID string, body io.Reader) (models.ProblemPackage, bool, error) {
+	_, span := tracing.Start(ctx, "cache.upload", trace.WithAttributes(attribute.String(logger.KeyProblemID, problemID)))
+	pkg, replaced, err := s.uploadPackage(problemID, body)
+	tracing.End(span, err)
+	return pkg, replaced, err
+}
+
+func (s *CacheService) uploadPackage(problemID string, body io.Reader) (models.ProblemPackage, bool, error) {
+	if !problemIDPattern.MatchString(problemID) {
+		return models.ProblemPackage{}, false, fmt.Errorf("%w: invalid problem id", customErrors.ErrValidation)
+	}
+
+	tmpZip, err := os.CreateTemp("", "problem-*.zip")
+	if err != nil {
+		return models.ProblemPackage{}, false, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmpZip.Name())
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tmpZip, hash), io.LimitReader(body, s.cacheConfig.MAXPACKAGEBYTES+1))
+	tmpZip.Close()
+	if err != nil {
+		return models.ProblemPackage{}, false, fmt.Errorf("failed to read package: %w", err)
+	}
+	if written > s.cacheConfig.MAXPACKAGEBYTES {
+		return models.ProblemPackage{}, false, fmt.Errorf("%w: limit is %d bytes", customErrors.ErrPackageTooLarge, s.cacheConfig.MAXPACKAGEBYTES)
+	}
+
+	origin := packageOrigin{Source: packageSourceUpload, SHA256: hex.EncodeToString(hash.Sum(nil))}
+	pkg, replaced, err := s.installPackage(problemID, tmpZip.Name(), origin)
+	if err != nil {
+		return models.ProblemPackage{}, false, err
+	}
+	s.logger.Info("Pacote do problema instalado por upload", logger.KeyProblemID, problemID, "version", pkg.Version, "replaced", replaced)
+	return pkg, replaced, nil
+}
+
+// Invalidate tira o problema do cache: a próxima consulta baixa o pacote de novo da API (ou responde
+// problema inexistente, com ONLY_LOCAL_CACHE). A versão removida continua no disco para os jobs
+// que a referenciam; só uma instalação seguinte a apaga, quando nenhum job a usar mais.
+func (s *CacheService) Invalidate(ctx context.Context, problemID string) error {
+	_, span := tracing.Start(ctx, "cache.invalidate", trace.WithAttributes(attribute.String(logger.KeyProblemID, problemID)))
+	err := s.invalidate(problemID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *CacheService) invalidate(problemID string) error {
+	if !problemIDPattern.MatchString(problemID) {
+		return fmt.Errorf("%w: invalid problem id", customErrors.ErrValidation)
+	}
+
+	s.installMu.Lock()
+	defer s.installMu.Unlock()
+
+	problemDir := filepath.Join(s.cacheConfig.CACHEDIRECTORY, problemID+s.cacheConfig.CACHEFILEEXTENSION)
+	versionsDir := filepath.Join(s.cacheConfig.CACHEDIRECTORY, versionsDirectory, problemID)
+
+	info, err := os.Lstat(problemDir)
+	switch {
+	case os.IsNotExist(err):
+		return fmt.Errorf("%w: %s is not in the cache", customErrors.ErrProblemNotFound, problemID)
+	case err != nil:
+		return fmt.Errorf("failed to inspect cached problem: %w", err)
+	case info.Mode()&os.ModeSymlink != 0:
+		err = os.Remove(problemDir)
+	default:
+		err = s.moveLegacyEntry(problemDir, versionsDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove cached problem: %w", err)
+	}
+
+	if err := os.Remove(filepath.Join(versionsDir, manifestFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove package manifest: %w", err)
+	}
+
+	s.logger.Info("Problema removido do cache", logger.KeyProblemID, problemID)
+	return nil
+}
+
+// installPackage extrai o .zip numa área temporária, valida o conteúdo com ValidatePackage e só
+// então troca o symlink do problema para a nova versão. Jobs na fila ou em execução com a versão
+// anterior não são afetados: pruneVersions não apaga as versões que eles referenciam.
+func (s *CacheService) installPackage(problemID string, zipPath string, origin packageOrigin) (models.ProblemPackage, bool, error) {
+	s.installMu.Lock()
+	defer s.installMu.Unlock()
+
+	versionsDir := filepath.Join(s.cacheConfig.CACHEDIRECTORY, versionsDirectory, problemID)
+	if err := os.MkdirAll(versionsDir, 0755); err != nil {
+		return models.ProblemPackage{}, false, fmt.Errorf("failed to create versions directory: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(versionsDir, ".staging-*")
+	if err != nil {
+		return models.ProblemPackage{}, false, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := ExtractPackage(zipPath, staging, s.cacheConfig.MAXFILEBYTES, s.cacheConfig.MAXEXTRACTEDBYTES); err != nil {
+		return models.ProblemPackage{}, false, err
+	}
+
+	report, err := ValidatePackage(staging)
+	if err != nil {
+		return models.ProblemPackage{}, false, err
+	}
+	if issues := report.Errors(); len(issues) > 0 {
+		return models.ProblemPackage{}, false, &customErrors.PackageError{Issues: issues}
+	}
+	for _, issue := range report.Warnings() {
+		s.logger.Warn("Aviso na validação do pacote", logger.KeyProblemID, problemID, "code", issue.Code, "file", issue.File, "message", issue.Message)
+	}
+
+	installedAt := time.Now().UTC()
+	pkg := models.ProblemPackage{
+		ProblemID:   problemID,
+		Version:     strconv.FormatInt(installedAt.UnixNano(), 10),
+		SHA256:      origin.SHA256,
+		TestCases:   report.TestCases,
+		Languages:   report.Languages,
+		InstalledAt: installedAt,
+		Warnings:    report.Warnings(),
+	}
+
+	// MkdirTemp cria o diretório como 0700; a versão instalada segue as permissões do resto do cache
+	if err := os.Chmod(staging, 0755); err != nil {
+		return models.ProblemPackage{}, false, fmt.Errorf("failed to store package version: %w", err)
+	}
+	versionDir := filepath.Join(versionsDir, pkg.Version)
+	if err := os.Rename(staging, versionDir); err != nil {
+		return models.ProblemPackage{}, false, fmt.Errorf("failed to store package version: %w", err)
+	}
+
+	problemDir := filepath.Join(s.cacheConfig.CACHEDIRECTORY, problemID+s.cacheConfig.CACHEFILEEXTENSION)
+	replaced, err := s.activateVersion(problemDir, versionsDir, pkg.Version)
+	if err != nil {
+		os.RemoveAll(versionDir)
+		return models.ProblemPackage{}, false, err
+	}
+
+	// sem o manifesto o pacote continua válido; só a próxima revalidação baixa o .zip inteiro de novo
+	err = s.writeManifest(problemID, packageManifest{
+		Version:      pkg.Version,
+		Source:       origin.Source,
+		SHA256:       origin.SHA256,
+		ETag:         origin.ETag,
+		LastModified: origin.LastModified,
+		InstalledAt:  installedAt,
+		CheckedAt:    installedAt,
+	})
+	if err != nil {
+		s.logger.Warn("Falha ao gravar o manifesto do pacote", logger.KeyProblemID, problemID, logger.Err(err))
+	}
+
+	s.pruneVersions(problemID, versionsDir, pkg.Version)
+	return pkg, replaced, nil
+}
+
+// activateVersion aponta problemDir para a versão informada e indica se ele já apontava para outra.
+func (s *CacheService) activateVersion(problemDir string, versionsDir string, version string) (bool, error) {
+	info, err := os.Lstat(problemDir)
+	replaced := err == nil
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return false, fmt.Errorf("failed to inspect cached problem: %w", err)
+	case info.Mode()&os.ModeSymlink == 0:
+		if err := s.moveLegacyEntry(problemDir, versionsDir); err != nil {
+			return false, fmt.Errorf("failed to move legacy cache entry: %w", err)
+		}
+	}
+
+	target, err := filepath.Rel(filepath.Dir(problemDir), filepath.Join(versionsDir, version))
+	if err != nil {
+		return false, err
+	}
+
+	// rename sobre o symlink existente é atômico: quem resolve o caminho vê a versão antiga ou a nova
+	tmpLink := filepath.Join(versionsDir, ".link-"+version)
+	if err := os.Symlink(target, tmpLink); err != nil {
+		return false, fmt.Errorf("failed to create cache link: %w", err)
+	}
+	if err := os.Rename(tmpLink, problemDir); err != nil {
+		os.Remove(tmpLink)
+		return false, fmt.Errorf("failed to activate package version: %w", err)
+	}
+	return replaced, nil
+}
+
+// moveLegacyEntry transforma um diretório de antes do versionamento em uma versão como as outras.
+func (s *CacheService) moveLegacyEntry(problemDir string, versionsDir string) error {
+	if err := os.MkdirAll(versionsDir, 0755); err != nil {
+		return err
+	}
+	legacy := "legacy-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	return os.Rename(problemDir, filepath.Join(versionsDir, legacy))
+}
+
+// pruneVersions apaga as versões antigas e restos de instalações interrompidas. Ficam a atual, as
+// que jobs na fila ou em execução ainda vão copiar e a mais recente das outras, que uma submissão
+// pode ter resolvido pouco antes da troca e ainda não gravou na fila.
+func (s *CacheService) pruneVersions(problemID string, versionsDir string, current string) {
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil {
+		return
+	}
+
+	inUse, err := s.repository.CachePathsInUse(problemID)
+	if err != nil {
+		s.logger.Warn("Falha ao consultar versões em uso, nenhuma foi removida", logger.KeyProblemID, problemID, logger.Err(err))
+		return
+	}
+	keep := map[string]bool{current: true, manifestFile: true}
+	for _, path := range inUse {
+		keep[filepath.Base(path)] = true
+	}
+
+	var previous string
+	var previousTime time.Time
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == current || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(previousTime) {
+			previous, previousTime = entry.Name(), info.ModTime()
+		}
+	}
+	keep[previous] = true
+
+	for _, entry := range entries {
+		if keep[entry.Name()] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(versionsDir, entry.Name())); err != nil {
+			s.logger.Warn("Falha ao remover versão antiga do pacote", "path", entry.Name(), logger.Err(err))
+		}
+	}
+}
+
+// readManifest lê o manifesto do problema; problemas sem manifesto (instalados antes do
+// versionamento) devolvem um manifesto vazio, que força a revalidação.
+func (s *CacheService) readManifest(problemID string) (packageManifest, error) {
+	var manifest packageManifest
+
+	data, err := os.ReadFile(filepath.Join(s.cacheConfig.CACHEDIRECTORY, versionsDirectory, problemID, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("corrupted %s: %w", manifestFile, err)
+	}
+	return manifest, nil
+}
+
+// writeManifest grava o manifesto por um arquivo temporário e rename; quem chama segura installMu.
+func (s *CacheService) writeManifest(problemID string, manifest packageManifest) error {
+	versionsDir := filepath.Join(s.cacheConfig.CACHEDIRECTORY, versionsDirectory, problemID)
+	if err := os.MkdirAll(versionsDir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(versionsDir, ".manifest-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(versionsDir, manifestFile))
+}
+
+// touchManifest registra uma revalidação que manteve a versão atual, atualizando os validadores
+// HTTP quando a API mandou novos.
+func (s *CacheService) touchManifest(problemID string, etag string, lastModified string) error {
+	s.installMu.Lock()
+	defer s.installMu.Unlock()
+
+	manifest, err := s.readManifest(problemID)
+	if err != nil {
+		return err
+	}
+	manifest.CheckedAt = time.Now().UTC()
+	if etag != "" {
+		manifest.ETag = etag
+	}
+	if lastModified != "" {
+		manifest.LastModified = lastModified
+	}
+	return s.writeManifest(problemID, manifest)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"IFJudger/internal/models"
+	"sync"
+)
+
+// tamanho do buffer de cada inscrito; um cliente lento perde eventos intermediários em vez de travar o worker
+const progressBufferSize = 64
+
+// progressBroker distribui em memória os eventos de progresso dos jobs executados
+// por esta instância para quem estiver acompanhando a submissão.
+type progressBroker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan models.ProgressEvent]struct{}
+}
+
+func newProgressBroker() *progressBroker {
+	return &progressBroker{
+		subscribers: make(map[string]map[chan models.ProgressEvent]struct{}),
+	}
+}
+
+func (b *progressBroker) subscribe(jobID string) (<-chan models.ProgressEvent, func()) {
+	ch := make(chan models.ProgressEvent, progressBufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[jobID] == nil {
+		b.subscribers[jobID] = make(map[chan models.ProgressEvent]struct{})
+	}
+	b.subscribers[jobID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if subs, ok := b.subscribers[jobID]; ok {
+			delete(subs, ch)
+			if len(subs) == 0 {
+				delete(b.subscribers, jobID)
+			}
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+func (b *progressBroker) publish(event models.ProgressEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[event.SubmissionID] {
+		select {
+		case ch <- event:
+		default:
+			// o evento final não pode se perder: descarta o mais antigo para abrir espaço
+			if event.IsFinal() {
+				select {
+				case <-ch:
+				default:
+				}
+				select {
+				case ch <- event:
+				default:
+				}
+			}
+		}
+	}
+}
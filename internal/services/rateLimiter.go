@@ -0,0 +1,57 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter é um token bucket por chave: cada chave acumula até perMinute
+// requisições, repostas continuamente ao longo do minuto.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens   float64
+	updated  time.Time
+	capacity float64
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// allow consome uma requisição do bucket da chave. Quando não há saldo,
+// retorna false e o tempo até a próxima requisição ser liberada.
+func (rl *rateLimiter) allow(key string, perMinute int) (bool, time.Duration) {
+	if perMinute <= 0 {
+		return true, 0
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	capacity := float64(perMinute)
+	ratePerSecond := capacity / 60
+
+	bucket, ok := rl.buckets[key]
+	if !ok || bucket.capacity != capacity {
+		bucket = &tokenBucket{tokens: capacity, updated: now, capacity: capacity}
+		rl.buckets[key] = bucket
+	}
+
+	bucket.tokens = min(capacity, bucket.tokens+now.Sub(bucket.updated).Seconds()*ratePerSecond)
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / ratePerSecond * float64(time.Second))
+		return false, wait
+	}
+
+	bucket.tokens--
+	return true, 0
+}
@@ -3,61 +3,167 @@ package services
 import (
 	"IFJudger/internal/models"
 	"IFJudger/internal/models/configs"
+	customErrors "IFJudger/internal/models/errors"
 	"IFJudger/internal/repository"
+	"IFJudger/pkg/logger"
+	"IFJudger/pkg/metrics"
+	"IFJudger/pkg/tracing"
 	"IFJudger/pkg/worker"
-	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
-	"net/http"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type WorkerService struct {
 	repository *repository.SubmissionRepository
+	callbacks  *CallbackService
 
 	config configs.WorkerServiceConfig
 
-	jobQueue   chan models.Job
+	instanceID string
+	logger     *slog.Logger
+	reaper     *worker.ContainerReaper
+	progress   *progressBroker
+	wakeup     chan struct{}
 	maxWorkers int
+	scheduler  *laneScheduler
+	// reservedLanes[i] é a lane exclusiva do worker i; vazio para workers compartilhados
+	reservedLanes []models.Priority
+
+	// stop é fechado no desligamento para que os workers parem de reservar jobs;
+	// jobsCtx é cancelado quando o prazo de drenagem acaba, abortando os containers em execução
+	draining   atomic.Bool
+	stop       chan struct{}
+	jobsCtx    context.Context
+	cancelJobs context.CancelFunc
+	workersWG  sync.WaitGroup
 }
 
+// intervalo máximo que um worker ocioso espera antes de consultar a fila de novo;
+// também é o que garante que jobs com lease expirado sejam retomados
+const queuePollInterval = 2 * time.Second
+
 var LanguageNotFound = errors.New("language not found")
 
-func StartWorkerService(config configs.WorkerServiceConfig, repository *repository.SubmissionRepository) (*WorkerService, error) {
-	log.Printf("[Init] Iniciando WorkerService com %d workers e fila de tamanho %d\n", config.MaxWorkers, config.QueueSize)
+func StartWorkerService(config configs.WorkerServiceConfig, repository *repository.SubmissionRepository, callbacks *CallbackService) (*WorkerService, error) {
+
+	weights, reservedLanes, err := buildLanes(config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
 
 	service := &WorkerService{
-		repository: repository,
-		config:     config,
-		jobQueue:   make(chan models.Job, config.QueueSize),
-		maxWorkers: config.MaxWorkers,
+		repository:    repository,
+		callbacks:     callbacks,
+		config:        config,
+		instanceID:    generateToken()[:8],
+		logger:        logger.Component("worker_service"),
+		progress:      newProgressBroker(),
+		wakeup:        make(chan struct{}, config.MaxWorkers),
+		maxWorkers:    config.MaxWorkers,
+		scheduler:     newLaneScheduler(weights),
+		reservedLanes: reservedLanes,
+		stop:          make(chan struct{}),
+		jobsCtx:       jobsCtx,
+		cancelJobs:    cancelJobs,
+	}
+
+	service.logger.Info("Iniciando WorkerService", "workers", config.MaxWorkers, "queue_size", config.QueueSize, "instance_id", service.instanceID)
+
+	metrics.Workers.Set(float64(config.MaxWorkers))
+	if err := metrics.Register(metrics.NewQueueCollector(repository.CountQueuedByPriority)); err != nil {
+		service.logger.Warn("Falha ao registrar a métrica da fila", logger.Err(err))
+	}
+
+	service.reaper, err = worker.NewContainerReaper()
+	if err != nil {
+		service.logger.Warn("Falha ao conectar no Docker, containers órfãos não serão removidos", logger.Err(err))
 	}
 
 	service.cleanupStaleWorkspaces()
+	service.cleanupOrphanContainers()
+
+	service.recoverJobs()
 
 	service.startWorkers()
 
-	go service.recoverJobs()
+	go service.reaperLoop()
 
 	return service, nil
 }
 
+// buildLanes valida os pesos e reservas de cada lane e decide qual lane cada worker atende.
+// Os primeiros workers ficam reservados (na ordem de models.Priorities); os restantes são compartilhados.
+func buildLanes(config configs.WorkerServiceConfig) (map[models.Priority]int, []models.Priority, error) {
+	weights := make(map[models.Priority]int)
+	for _, lane := range models.Priorities {
+		weights[lane] = 1
+	}
+	for token, weight := range config.LaneWeights {
+		lane, err := models.ParsePriority(token)
+		if err != nil {
+			return nil, nil, err
+		}
+		if weight <= 0 {
+			return nil, nil, fmt.Errorf("weight of lane %s must be positive", lane)
+		}
+		weights[lane] = weight
+	}
+
+	reserved := make(map[models.Priority]int)
+	totalReserved := 0
+	for token, count := range config.LaneReservedWorkers {
+		lane, err := models.ParsePriority(token)
+		if err != nil {
+			return nil, nil, err
+		}
+		if count < 0 {
+			return nil, nil, fmt.Errorf("reserved workers of lane %s cannot be negative", lane)
+		}
+		reserved[lane] = count
+		totalReserved += count
+	}
+	if totalReserved > config.MaxWorkers {
+		return nil, nil, fmt.Errorf("%d reserved workers exceed MaxWorkers (%d)", totalReserved, config.MaxWorkers)
+	}
+
+	reservedLanes := make([]models.Priority, config.MaxWorkers)
+	workerID := 0
+	for _, lane := range models.Priorities {
+		for i := 0; i < reserved[lane]; i++ {
+			reservedLanes[workerID] = lane
+			workerID++
+		}
+	}
+
+	return weights, reservedLanes, nil
+}
+
 func (s *WorkerService) cleanupStaleWorkspaces() {
 	dir := s.config.ExecutionDirectory
-	log.Printf("[Cleanup] Verificando lixo em %s...\n", dir)
+	s.logger.Debug("Verificando workspaces antigos", "dir", dir)
 
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return
 		}
-		log.Printf("[Cleanup] Erro ao ler diretório: %v\n", err)
+		s.logger.Error("Erro ao ler diretório de execuções", "dir", dir, logger.Err(err))
 		return
 	}
 
@@ -66,93 +172,385 @@ func (s *WorkerService) cleanupStaleWorkspaces() {
 		if e.IsDir() && strings.HasPrefix(e.Name(), "job-") {
 			fullPath := filepath.Join(dir, e.Name())
 			if err := os.RemoveAll(fullPath); err != nil {
-				log.Printf("[Cleanup] Falha ao remover %s: %v\n", e.Name(), err)
+				s.logger.Warn("Falha ao remover workspace antigo", "path", fullPath, logger.Err(err))
 			} else {
 				count++
 			}
 		}
 	}
 	if count > 0 {
-		log.Printf("[Cleanup] %d pastas temporárias antigas removidas.\n", count)
+		s.logger.Info("Workspaces antigos removidos", "count", count)
 	}
 }
 
-func (s *WorkerService) recoverJobs() {
-	log.Println("[Recovery] Verificando jobs pendentes no banco...")
+// cleanupOrphanContainers remove containers criados pelo judger cujo job não está mais em execução,
+// como os que sobram quando o processo cai no meio de uma avaliação.
+func (s *WorkerService) cleanupOrphanContainers() {
+	if s.reaper == nil {
+		return
+	}
 
-	jobs, err := s.repository.GetRecoverableJobs()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// a lista de containers precisa ser lida antes dos jobs ativos: um container só existe
+	// depois que seu job foi reservado, então nenhum container em uso aparece como órfão
+	containers, err := s.reaper.ListManaged(ctx)
 	if err != nil {
-		log.Printf("[Recovery] Erro ao buscar jobs: %v\n", err)
+		s.logger.Error("Erro ao listar containers", logger.Err(err))
+		return
+	}
+	if len(containers) == 0 {
 		return
 	}
 
-	if len(jobs) == 0 {
-		log.Println("[Recovery] Nenhum job pendente encontrado.")
+	activeJobs, err := s.repository.GetLeasedJobIDs()
+	if err != nil {
+		s.logger.Error("Erro ao buscar jobs em execução", logger.Err(err))
 		return
 	}
 
-	log.Printf("[Recovery] %d jobs encontrados. Re-enfileirando...\n", len(jobs))
+	count := 0
+	for _, c := range containers {
+		if activeJobs[c.JobID] {
+			continue
+		}
 
-	for _, job := range jobs {
-		s.jobQueue <- job
-		log.Printf("[Recovery] Job %s recuperado e re-enfileirado.\n", job.ID)
+		if err := s.reaper.Remove(ctx, c.ID); err != nil {
+			s.logger.Warn("Falha ao remover container órfão", logger.KeyContainerID, c.ID, logger.KeyJobID, c.JobID, logger.Err(err))
+			continue
+		}
+		count++
+	}
+	if count > 0 {
+		s.logger.Info("Containers órfãos removidos", "count", count)
 	}
 }
 
+func (s *WorkerService) reaperLoop() {
+	if s.reaper == nil || s.config.ReaperInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.ReaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.cleanupOrphanContainers()
+		}
+	}
+}
+
+func (s *WorkerService) recoverJobs() {
+	s.logger.Debug("Verificando jobs pendentes no banco")
+
+	queued, err := s.repository.CountByStatus(models.StatusQueued)
+	if err != nil {
+		s.logger.Error("Erro ao contar jobs pendentes", logger.Err(err))
+		return
+	}
+
+	processing, err := s.repository.CountByStatus(models.StatusProcessing)
+	if err != nil {
+		s.logger.Error("Erro ao contar jobs pendentes", logger.Err(err))
+		return
+	}
+
+	if queued+processing == 0 {
+		s.logger.Info("Nenhum job pendente encontrado.")
+		return
+	}
+
+	// jobs em processing de uma execução anterior são retomados quando o lease expirar
+	s.logger.Info("Jobs pendentes serão retomados pelos workers", "queued", queued, "processing", processing)
+}
+
 func (s *WorkerService) startWorkers() {
 	for i := 0; i < s.maxWorkers; i++ {
-		go s.workerLoop(i)
+		s.workersWG.Add(1)
+		go func(workerID int) {
+			defer s.workersWG.Done()
+			s.workerLoop(workerID)
+		}(i)
+	}
+}
+
+// Shutdown para de aceitar jobs e espera os workers terminarem o que estão executando.
+// Se ctx expirar antes, os containers em execução são abortados e seus jobs voltam para a fila,
+// para serem retomados na próxima inicialização.
+func (s *WorkerService) Shutdown(ctx context.Context) error {
+	if !s.draining.CompareAndSwap(false, true) {
+		return nil
 	}
+
+	s.logger.Info("Parando de aceitar jobs e aguardando workers...")
+	close(s.stop)
+
+	done := make(chan struct{})
+	go func() {
+		s.workersWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.logger.Info("Todos os workers terminaram.")
+		return nil
+
+	case <-ctx.Done():
+		s.logger.Warn("Prazo esgotado, abortando jobs em execução e devolvendo para a fila...")
+		s.cancelJobs()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (s *WorkerService) leaseOwner(workerID int) string {
+	return fmt.Sprintf("%s-%d", s.instanceID, workerID)
 }
 
 func (s *WorkerService) workerLoop(workerID int) {
-	log.Printf("[Worker-%d] Pronto e aguardando jobs...\n", workerID)
+	workerLog := s.logger.With(logger.KeyWorkerID, workerID)
+	if lane := s.reservedLanes[workerID]; lane != "" {
+		workerLog.Info("Pronto e aguardando jobs", "reserved_lane", lane)
+	} else {
+		workerLog.Info("Pronto e aguardando jobs")
+	}
+
 	for {
-		job, isOpen := <-s.jobQueue
-		if !isOpen {
-			log.Printf("[Worker-%d] Canal fechado. Encerrando.\n", workerID)
-			break
+		select {
+		case <-s.stop:
+			workerLog.Info("Desligamento solicitado. Encerrando.")
+			return
+		default:
+		}
+
+		job, found := s.claimJob(workerID)
+		if !found {
+			select {
+			case <-s.stop:
+			case <-s.wakeup:
+			case <-time.After(queuePollInterval):
+			}
+			continue
 		}
 
-		log.Printf("[Worker-%d] Pegou o Job %s. Processando...\n", workerID, job.ID)
 		s.processJob(job, workerID)
-		log.Printf("[Worker-%d] Terminou o Job %s. Voltando a dormir.\n", workerID, job.ID)
 	}
 }
 
+// claimJob tenta reservar um job nas lanes que o worker atende, na ordem definida pelo scheduler.
+func (s *WorkerService) claimJob(workerID int) (models.Job, bool) {
+	owner := s.leaseOwner(workerID)
+
+	lanes := s.scheduler.next()
+	if lane := s.reservedLanes[workerID]; lane != "" {
+		lanes = []models.Priority{lane}
+	}
+
+	for _, lane := range lanes {
+		job, err := s.repository.ClaimNextJob(owner, lane, s.config.LeaseTimeout)
+		if err == nil {
+			return job, true
+		}
+		if !errors.Is(err, customErrors.ErrNotFound) {
+			s.logger.Error("Erro ao buscar job", logger.KeyWorkerID, workerID, "lane", lane, logger.Err(err))
+		}
+	}
+
+	return models.Job{}, false
+}
+
+// jobLogger retorna um logger com os atributos que identificam o job em todos os serviços.
+func (s *WorkerService) jobLogger(job models.Job, workerID int) *slog.Logger {
+	return s.logger.With(
+		logger.KeyJobID, job.ID,
+		logger.KeyProblemID, job.ProblemID,
+		logger.KeyWorkerID, workerID,
+		"priority", job.Priority,
+	)
+}
+
 func (s *WorkerService) processJob(job models.Job, workerID int) {
-	s.updateResult(job.ID, models.StatusProcessing, models.ExecutionReport{}, "")
+	jobLog := s.jobLogger(job, workerID)
+	jobLog.Info("Job reservado, processando")
 
-	result, err := s.executeWorker(job, workerID)
+	// o trace continua o da requisição que criou o job; jobsCtx segue controlando o cancelamento
+	ctx := tracing.Extract(s.jobsCtx, job.TraceContext)
+
+	start := time.Now()
+	if !job.EnqueuedAt.IsZero() {
+		metrics.JobStageDuration.WithLabelValues(metrics.StageQueueWait).Observe(start.Sub(job.EnqueuedAt).Seconds())
+		_, waitSpan := tracing.Start(ctx, "job.queue_wait", trace.WithTimestamp(job.EnqueuedAt))
+		waitSpan.End(trace.WithTimestamp(start))
+	}
+
+	ctx, span := tracing.Start(ctx, "job.process", trace.WithAttributes(
+		attribute.String(logger.KeyJobID, job.ID),
+		attribute.String(logger.KeyProblemID, job.ProblemID),
+		attribute.Int(logger.KeyWorkerID, workerID),
+		attribute.String("job.priority", string(job.Priority)),
+	))
+	defer span.End()
+
+	metrics.ActiveWorkers.Inc()
+	defer metrics.ActiveWorkers.Dec()
+
+	if job.Attempts > s.config.MaxAttempts {
+		message := fmt.Sprintf("job abandoned after %d attempts", s.config.MaxAttempts)
+		jobLog.Error("Job excedeu o limite de tentativas, encerrando sem executar", "attempts", job.Attempts)
+		span.SetStatus(codes.Error, message)
+		s.recordVerdict(job, models.StatusError)
+		if s.updateResult(ctx, job.ID, workerID, models.StatusError, models.ExecutionReport{}, message, jobLog) {
+			s.notify(ctx, job, models.StatusError, nil, message)
+		}
+		return
+	}
+
+	s.notify(ctx, job, models.StatusProcessing, nil, "")
+
+	ctx, cancelJob := context.WithCancel(ctx)
+	defer cancelJob()
+
+	stopHeartbeat := s.keepLease(job.ID, workerID, cancelJob, jobLog)
+	result, err := s.executeWorker(ctx, job, jobLog)
+	leaseLost := stopHeartbeat()
+
+	// o novo dono executa o job de novo e grava o resultado; este não tem mais o que fazer
+	if leaseLost {
+		jobLog.Warn("Lease perdido durante a execução, descartando o resultado")
+		return
+	}
+
+	if err != nil && s.jobsCtx.Err() != nil {
+		jobLog.Warn("Job interrompido pelo desligamento, devolvendo para a fila")
+		if err := s.repository.RequeueJob(job.ID, s.leaseOwner(workerID)); err != nil {
+			jobLog.Error("Falha ao devolver job para a fila", logger.Err(err))
+		}
+		s.progress.publish(models.ProgressEvent{Type: models.ProgressQueued, SubmissionID: job.ID, Status: models.StatusQueued})
+		return
+	}
+
+	metrics.JobStageDuration.WithLabelValues(metrics.StageTotal).Observe(time.Since(start).Seconds())
 
 	if err != nil {
-		log.Printf("[Worker-%d] ERRO no Job %s: %v\n", workerID, job.ID, err)
-		s.updateResult(job.ID, models.StatusError, models.ExecutionReport{}, err.Error())
+		jobLog.Error("Job terminou com erro", "duration_ms", time.Since(start).Milliseconds(), logger.Err(err))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		s.recordVerdict(job, models.StatusError)
+		if s.updateResult(ctx, job.ID, workerID, models.StatusError, models.ExecutionReport{}, err.Error(), jobLog) {
+			s.notify(ctx, job, models.StatusError, nil, err.Error())
+		}
 		return
 	}
 
-	log.Printf("[Worker-%d] SUCESSO no Job %s\n", workerID, job.ID)
-	s.updateResult(job.ID, models.StatusSuccess, result, "")
+	jobLog.Info("Job concluído", "verdict", result.Verdict(), "duration_ms", time.Since(start).Milliseconds())
+	span.SetAttributes(attribute.String("job.verdict", result.Verdict()))
+	s.recordVerdict(job, result.Verdict())
+	if s.updateResult(ctx, job.ID, workerID, models.StatusSuccess, result, "", jobLog) {
+		s.notify(ctx, job, models.StatusSuccess, &result, "")
+	}
+}
+
+// recordVerdict contabiliza o veredicto das submissões; execuções do modo run não têm problema e ficam de fora.
+func (s *WorkerService) recordVerdict(job models.Job, verdict string) {
+	if job.Kind == models.JobKindRun {
+		return
+	}
+	metrics.Verdicts.WithLabelValues(job.LanguageID.Token(), job.ProblemID, verdict).Inc()
+}
+
+// notify publica a mudança de status do job no outbox de callbacks e para quem acompanha a submissão.
+func (s *WorkerService) notify(ctx context.Context, job models.Job, status string, result *models.ExecutionReport, errMsg string) {
+	progress := models.ProgressFromResult(models.JobResult{ID: job.ID, Status: status, ErrorMessage: errMsg})
+	progress.Result = result
+	s.progress.publish(progress)
+
+	event := models.CallbackEvent{
+		SubmissionID: job.ID,
+		Status:       status,
+		Result:       result,
+		ErrorMessage: errMsg,
+		Timestamp:    time.Now().UTC(),
+	}
+
+	switch status {
+	case models.StatusProcessing:
+		event.Event = models.EventSubmissionProcessing
+	case models.StatusSuccess:
+		event.Event = models.EventSubmissionCompleted
+	default:
+		event.Event = models.EventSubmissionFailed
+	}
+
+	// execuções do modo run só geram callback quando o cliente pediu um webhook
+	if job.Kind == models.JobKindRun && job.WebhookURL == "" {
+		return
+	}
 
-	jobResult, _ := s.GetResult(job.ID)
-	go s.sendCallback(&jobResult)
+	s.callbacks.Enqueue(ctx, event, job.WebhookURL)
 }
 
-func (s *WorkerService) executeWorker(job models.Job, workerID int) (models.ExecutionReport, error) {
-	log.Printf("[Worker-%d] -> Criando container Docker (RAM: %dMB, Timeout: %s)...\n", workerID, job.MaximumRamMB, job.TimeLimit)
+// keepLease renova o lease do job periodicamente enquanto ele executa,
+// para que execuções longas não sejam retomadas por outro worker. Se o lease já estiver com
+// outro worker, cancelJob aborta a execução. A função devolvida para a renovação e indica se o
+// lease foi perdido.
+func (s *WorkerService) keepLease(jobID string, workerID int, cancelJob context.CancelFunc, jobLog *slog.Logger) func() bool {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	owner := s.leaseOwner(workerID)
+	lost := false
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(s.config.LeaseTimeout / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := s.repository.RenewLease(jobID, owner, s.config.LeaseTimeout)
+				if errors.Is(err, customErrors.ErrNotFound) {
+					jobLog.Warn("Lease assumido por outro worker, abortando a execução")
+					lost = true
+					cancelJob()
+					return
+				}
+				if err != nil {
+					jobLog.Warn("Falha ao renovar lease", logger.Err(err))
+				}
+			}
+		}
+	}()
 
+	return func() bool {
+		close(done)
+		<-stopped
+		return lost
+	}
+}
+
+func (s *WorkerService) executeWorker(ctx context.Context, job models.Job, jobLog *slog.Logger) (models.ExecutionReport, error) {
 	w, err := worker.NewWorker(worker.WorkerConfigData{
 		ContainerTimeout: s.config.ContainerTimeout,
 		TestTimeout:      job.TimeLimit,
 		MaximumRamMB:     job.MaximumRamMB,
+		JobID:            job.ID,
+		InstanceID:       s.instanceID,
+		Logger:           jobLog,
 	})
 	if err != nil {
 		return models.ExecutionReport{}, fmt.Errorf("falha newWorker: %w", err)
 	}
 	defer w.Cleanup()
 
-	log.Printf("[Worker-%d] -> Preparando Workspace em %s...\n", workerID, s.config.ExecutionDirectory)
-	err = w.PrepareWorkspace(worker.DockerWorkspaceConfig{
+	err = w.PrepareWorkspace(ctx, worker.DockerWorkspaceConfig{
 		CachePath:          job.CachePath,
 		ExecutionDirectory: s.config.ExecutionDirectory,
 		RunnerPath:         s.config.RunnerPath,
@@ -161,24 +559,44 @@ func (s *WorkerService) executeWorker(job models.Job, workerID int) (models.Exec
 		return models.ExecutionReport{}, fmt.Errorf("falha prepareWorkspace: %w", err)
 	}
 
-	if job.LanguageID == models.Python {
-		log.Printf("[Worker-%d] -> Configurando Python...\n", workerID)
-		err = w.SetupPython(job.Code)
-		if err != nil {
-			return models.ExecutionReport{}, fmt.Errorf("falha setupPython: %w", err)
+	if job.Kind == models.JobKindRun {
+		if err := w.SetupRun(job.Input, job.OutputLimitBytes); err != nil {
+			return models.ExecutionReport{}, fmt.Errorf("falha setupRun: %w", err)
 		}
-	} else {
+	}
+
+	language, ok := job.LanguageID.Language()
+	if !ok {
 		return models.ExecutionReport{}, fmt.Errorf("invalid language ID: %v", job.LanguageID)
 	}
+	err = w.SetupLanguage(worker.LanguageConfig{
+		Image:      language.Image,
+		SourceFile: language.SourceFile(),
+		RunCommand: language.RunCommand,
+	}, job.Code)
+	if err != nil {
+		return models.ExecutionReport{}, fmt.Errorf("falha setupLanguage: %w", err)
+	}
+
+	w.OnProgress(func(tc worker.TestCaseResult) {
+		s.progress.publish(models.ProgressEvent{
+			Type:         models.ProgressTestCase,
+			SubmissionID: job.ID,
+			Status:       models.StatusProcessing,
+			TestCase: &models.TestCaseResult{
+				ID:      tc.ID,
+				Status:  tc.Status,
+				TimeMS:  tc.TimeMS,
+				Message: tc.Message,
+			},
+		})
+	})
 
-	log.Printf("[Worker-%d] -> Executando Container...\n", workerID)
-	workerResult, err := w.Execute()
+	workerResult, err := w.Execute(ctx)
 	if err != nil {
 		return models.ExecutionReport{}, fmt.Errorf("falha execute: %w", err)
 	}
 
-	log.Printf("[Worker-%d] -> Container finalizado. Resultado lido.\n", workerID)
-
 	return mapToDomainReport(workerResult), nil
 }
 
@@ -194,32 +612,146 @@ func mapToDomainReport(wr worker.ExecutionReport) models.ExecutionReport {
 		}
 	}
 
-	return models.ExecutionReport{
+	report := models.ExecutionReport{
 		Results: domainResults,
 	}
+
+	if wr.Run != nil {
+		report.Run = &models.RunResult{
+			Status:    wr.Run.Status,
+			Stdout:    wr.Run.Stdout,
+			Stderr:    wr.Run.Stderr,
+			Truncated: wr.Run.Truncated,
+			ExitCode:  wr.Run.ExitCode,
+			TimeMS:    wr.Run.TimeMS,
+			MemoryKB:  wr.Run.MemoryKB,
+		}
+	}
+
+	return report
 }
 
-func (s *WorkerService) EnqueueJob(job models.Job) (string, error) {
+// EnqueueJob grava o job na fila. clientQuota > 0 limita os jobs ativos de job.ClientID.
+// Se job.IdempotencyKey já foi usada pelo cliente dentro de IdempotencyTTL, nenhum job é criado:
+// o id da submissão original é devolvido com replayed = true.
+func (s *WorkerService) EnqueueJob(ctx context.Context, job models.Job, clientQuota int) (id string, replayed bool, err error) {
+	if s.draining.Load() {
+		return "", false, customErrors.ErrShuttingDown
+	}
+
+	idempotencySince := time.Now().Add(-s.config.IdempotencyTTL)
+	if job.IdempotencyKey != "" {
+		if id, err := s.findIdempotentJob(job, idempotencySince); !errors.Is(err, customErrors.ErrNotFound) {
+			return id, err == nil, err
+		}
+	}
+
 	jobID := generateToken()
 	job.ID = jobID
+	job.EnqueuedAt = time.Now()
+	job.TraceContext = tracing.Inject(ctx)
 
-	log.Printf("[API] Tentando enfileirar Job %s...\n", jobID)
+	jobLog := s.logger.With(logger.KeyJobID, jobID, logger.KeyProblemID, job.ProblemID, "priority", job.Priority, "kind", job.Kind)
 
-	if err := s.repository.CreateJob(job); err != nil {
-		log.Printf("[API] ERRO CRÍTICO: Falha ao salvar job no banco: %v\n", err)
-		return "", fmt.Errorf("database error")
+	_, span := tracing.Start(ctx, "db.create_job", trace.WithAttributes(attribute.String(logger.KeyJobID, jobID)))
+	err = s.repository.CreateJob(job, s.config.QueueSize, clientQuota, idempotencySince)
+	tracing.End(span, err)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrDuplicateIdempotencyKey) {
+			// outra requisição com a mesma chave foi gravada entre a busca e a inserção
+			id, err := s.findIdempotentJob(job, idempotencySince)
+			return id, err == nil, err
+		}
+		if errors.Is(err, customErrors.ErrQuotaExceeded) {
+			jobLog.Warn("Cliente atingiu a cota de jobs ativos, rejeitando job", "client_id", job.ClientID, "quota", clientQuota)
+			return "", false, err
+		}
+		if errors.Is(err, customErrors.ErrQueueFull) {
+			jobLog.Warn("Fila cheia, rejeitando job")
+			return "", false, fmt.Errorf("server is busy: %w", err)
+		}
+		jobLog.Error("Falha ao salvar job no banco", logger.Err(err))
+		return "", false, fmt.Errorf("database error")
 	}
 
-	select {
-	case s.jobQueue <- job:
-		log.Printf("[API] Job %s entrou na fila.\n", jobID)
-		return jobID, nil
+	jobLog.Info("Job entrou na fila")
+	s.notifyWorkers()
+	return jobID, false, nil
+}
 
-	default:
-		log.Printf("[API] WARN: Fila cheia! Rejeitando Job %s.\n", jobID)
+// EnqueueBatch grava os jobs de um lote na fila em uma única transação e devolve o id do lote
+// e os ids dos jobs, na mesma ordem. As regras de EnqueueJob valem para o lote inteiro.
+func (s *WorkerService) EnqueueBatch(ctx context.Context, clientID string, jobs []models.Job, clientQuota int) (string, []string, error) {
+	if s.draining.Load() {
+		return "", nil, customErrors.ErrShuttingDown
+	}
+	if len(jobs) > s.config.QueueSize {
+		return "", nil, fmt.Errorf("%w: batch has %d submissions, larger than the queue (%d)", customErrors.ErrValidation, len(jobs), s.config.QueueSize)
+	}
+
+	batch := models.Batch{
+		ID:       generateToken(),
+		ClientID: clientID,
+		Total:    len(jobs),
+	}
+
+	traceContext := tracing.Inject(ctx)
+	ids := make([]string, len(jobs))
+	for i := range jobs {
+		jobs[i].ID = generateToken()
+		jobs[i].EnqueuedAt = time.Now()
+		jobs[i].TraceContext = traceContext
+		ids[i] = jobs[i].ID
+	}
+
+	batchLog := s.logger.With("batch_id", batch.ID, "submissions", len(jobs))
+
+	_, span := tracing.Start(ctx, "db.create_batch", trace.WithAttributes(attribute.String("batch_id", batch.ID), attribute.Int("submissions", len(jobs))))
+	err := s.repository.CreateBatch(batch, jobs, s.config.QueueSize, clientQuota)
+	tracing.End(span, err)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrQuotaExceeded) {
+			batchLog.Warn("Lote ultrapassa a cota de jobs ativos do cliente, rejeitando lote", "client_id", clientID, "quota", clientQuota)
+			return "", nil, err
+		}
+		if errors.Is(err, customErrors.ErrQueueFull) {
+			batchLog.Warn("Fila sem espaço para o lote, rejeitando lote")
+			return "", nil, fmt.Errorf("server is busy: %w", err)
+		}
+		batchLog.Error("Falha ao salvar lote no banco", logger.Err(err))
+		return "", nil, fmt.Errorf("database error")
+	}
+
+	batchLog.Info("Lote entrou na fila")
+	s.notifyWorkers()
+	return batch.ID, ids, nil
+}
+
+// findIdempotentJob devolve a submissão criada com a mesma Idempotency-Key, ou
+// customErrors.ErrIdempotencyConflict se ela foi criada a partir de outra requisição.
+func (s *WorkerService) findIdempotentJob(job models.Job, since time.Time) (string, error) {
+	id, hash, err := s.repository.FindByIdempotencyKey(job.ClientID, job.IdempotencyKey, since)
+	if err != nil {
+		return "", err
+	}
+	if hash != job.IdempotencyHash {
+		s.logger.Warn("Idempotency-Key reutilizada com outra requisição", logger.KeyJobID, id, "client_id", job.ClientID)
+		return "", customErrors.ErrIdempotencyConflict
+	}
+
+	s.logger.Info("Idempotency-Key repetida, devolvendo a submissão original", logger.KeyJobID, id, "client_id", job.ClientID)
+	return id, nil
+}
 
-		s.updateResult(jobID, models.StatusError, models.ExecutionReport{}, "Job Rejected, queue is full")
-		return "", fmt.Errorf("server is busy (queue full)")
+// notifyWorkers acorda os workers ociosos sem bloquear caso todos estejam ocupados.
+// Todos são acordados porque só os que atendem a lane do job conseguirão reservá-lo.
+func (s *WorkerService) notifyWorkers() {
+	for i := 0; i < s.maxWorkers; i++ {
+		select {
+		case s.wakeup <- struct{}{}:
+		default:
+			return
+		}
 	}
 }
 
@@ -229,39 +761,69 @@ func generateToken() string {
 	return hex.EncodeToString(b)
 }
 
-func (s *WorkerService) updateResult(token, status string, result models.ExecutionReport, err string) {
+// updateResult grava o resultado do job e indica se o resultado deve ser notificado. Se o lease
+// expirou e outro worker retomou o job, o resultado é descartado: quem notifica é o novo dono.
+func (s *WorkerService) updateResult(ctx context.Context, token string, workerID int, status string, result models.ExecutionReport, err string, jobLog *slog.Logger) bool {
+	_, span := tracing.Start(ctx, "db.update_result")
 	dbErr := s.repository.UpdateResult(models.JobResult{
 		ID:           token,
 		Status:       status,
 		Result:       result,
 		ErrorMessage: err,
-	})
+	}, s.leaseOwner(workerID))
+	tracing.End(span, dbErr)
 
+	if errors.Is(dbErr, customErrors.ErrNotFound) {
+		jobLog.Warn("Lease perdido antes de gravar o resultado, descartando")
+		return false
+	}
 	if dbErr != nil {
-		log.Printf("[ERROR] Falha ao atualizar job %s no banco: %v", token, dbErr)
+		jobLog.Error("Falha ao atualizar job no banco", logger.Err(dbErr))
 	}
+	return true
 }
 
-func (s *WorkerService) GetResult(token string) (models.JobResult, bool) {
-	result, err := s.repository.GetByID(token)
-	if err != nil {
-		return models.JobResult{}, false
-	}
-	return result, true
+// IsDraining indica que o serviço recebeu o sinal de desligamento e não aceita novos jobs.
+func (s *WorkerService) IsDraining() bool {
+	return s.draining.Load()
 }
 
-func (s *WorkerService) sendCallback(result *models.JobResult) {
-	jsonData, err := json.Marshal(result)
-	if err != nil {
-		fmt.Printf("Erro ao serializar: %v\n", err)
-		return
-	}
+// QueueDepth retorna quantos jobs aguardam um worker.
+func (s *WorkerService) QueueDepth() (int, error) {
+	return s.repository.CountByStatus(models.StatusQueued)
+}
+
+// Subscribe acompanha os eventos de progresso de um job executado por esta instância.
+// A função retornada cancela a inscrição.
+func (s *WorkerService) Subscribe(token string) (<-chan models.ProgressEvent, func()) {
+	return s.progress.subscribe(token)
+}
+
+func (s *WorkerService) ListSubmissions(filter models.SubmissionFilter) (models.SubmissionPage, error) {
+	return s.repository.ListSubmissions(filter)
+}
 
-	resp, err := http.Post(s.config.CallbackUrl, "application/json", bytes.NewBuffer(jsonData))
+func (s *WorkerService) GetResult(token string) (models.JobResult, error) {
+	return s.repository.GetByID(token)
+}
+
+// CancelJob cancela um job que ainda aguarda na fila; com clientID preenchido, só se for desse
+// cliente. Quem acompanha a submissão e o callback recebem o mesmo evento de falha de um job que
+// terminou com erro.
+func (s *WorkerService) CancelJob(ctx context.Context, id, clientID string) (models.JobResult, error) {
+	job, err := s.repository.CancelQueued(id, clientID, canceledMessage)
 	if err != nil {
-		fmt.Printf("Erro na requisição: %v\n", err)
-		return
+		return models.JobResult{}, err
 	}
 
-	defer resp.Body.Close()
+	s.logger.Info("Job cancelado antes da execução", logger.KeyJobID, id, "client_id", job.ClientID)
+	s.notify(ctx, job, models.StatusError, nil, canceledMessage)
+	return models.JobResult{ID: id, Status: models.StatusError, ErrorMessage: canceledMessage}, nil
+}
+
+// mensagem de erro gravada nas submissões canceladas
+const canceledMessage = "canceled by client"
+
+func (s *WorkerService) GetBatch(id string) (models.BatchStatus, error) {
+	return s.repository.GetBatch(id)
 }
@@ -0,0 +1,318 @@
+// Package client é o cliente Go da API do judger (/v1).
+//
+// Os tipos e métodos seguem a especificação em internal/api/openapi/openapi.json, servida pelo
+// judger em GET /v1/openapi.json; ao mudar uma rota ou DTO, atualize os dois juntos.
+// Erros da API são devolvidos como *APIError, com o código do envelope de erro.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiPrefix = "/v1"
+
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type Option func(*Client)
+
+// WithAPIKey envia a chave em "Authorization: Bearer" em todas as requisições.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithHTTPClient troca o http.Client padrão, por exemplo para definir timeouts ou transporte.
+// WatchSubmission mantém a conexão aberta, então evite Timeout no cliente usado com ele.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// New cria um cliente para o judger em baseURL (ex.: "http://localhost:8080").
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// APIError é uma resposta de erro do judger.
+type APIError struct {
+	StatusCode int
+	Code       string // ex.: "validation_failed", "queue_full"
+	Message    string
+	Details    map[string]any
+	// RetryAfter vem do header Retry-After nas respostas 429 e 503
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("judger: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Submit enfileira uma submissão para correção e devolve o token para acompanhá-la.
+func (c *Client) Submit(ctx context.Context, req SubmissionRequest) (EnqueueResponse, error) {
+	header := http.Header{}
+	if req.IdempotencyKey != "" {
+		header.Set("Idempotency-Key", req.IdempotencyKey)
+	}
+
+	resp, err := c.send(ctx, http.MethodPost, "/submit", nil, header, req, "application/json")
+	if err != nil {
+		return EnqueueResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var response EnqueueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return EnqueueResponse{}, fmt.Errorf("judger: invalid response body: %w", err)
+	}
+	response.Replayed = resp.Header.Get("Idempotent-Replayed") == "true"
+	return response, nil
+}
+
+// SubmitBatch enfileira várias submissões de uma vez; ou todas entram na fila, ou nenhuma.
+// Se alguma for inválida, o *APIError traz o motivo de cada uma em Details["items"].
+func (c *Client) SubmitBatch(ctx context.Context, submissions []SubmissionRequest) (BatchResponse, error) {
+	var response BatchResponse
+	err := c.do(ctx, http.MethodPost, "/submit/batch", nil, BatchRequest{Submissions: submissions}, &response)
+	return response, err
+}
+
+// GetBatch devolve o progresso agregado do lote e o estado de cada submissão.
+func (c *Client) GetBatch(ctx context.Context, batchID string) (BatchStatus, error) {
+	var response BatchStatus
+	err := c.do(ctx, http.MethodGet, "/batches/"+url.PathEscape(batchID), nil, nil, &response)
+	return response, err
+}
+
+// GetSubmission devolve o estado e, quando terminada, o resultado da submissão.
+func (c *Client) GetSubmission(ctx context.Context, token string) (Submission, error) {
+	var response Submission
+	err := c.do(ctx, http.MethodGet, "/job", url.Values{"token": {token}}, nil, &response)
+	return response, err
+}
+
+// ListSubmissions busca uma página de submissões; use SubmissionPage.NextCursor em params.Cursor para a próxima.
+func (c *Client) ListSubmissions(ctx context.Context, params ListSubmissionsParams) (SubmissionPage, error) {
+	query := url.Values{}
+	setIfNotEmpty(query, "status", params.Status)
+	setIfNotEmpty(query, "problem_id", params.ProblemID)
+	setIfNotEmpty(query, "language", params.Language)
+	setIfNotEmpty(query, "priority", params.Priority)
+	setIfNotEmpty(query, "cursor", params.Cursor)
+	if !params.From.IsZero() {
+		query.Set("from", params.From.Format(time.RFC3339))
+	}
+	if !params.To.IsZero() {
+		query.Set("to", params.To.Format(time.RFC3339))
+	}
+	if params.Ascending {
+		query.Set("order", "asc")
+	}
+	if params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+
+	var response SubmissionPage
+	err := c.do(ctx, http.MethodGet, "/submissions", query, nil, &response)
+	return response, err
+}
+
+// Run executa o código uma vez com a entrada informada, sem casos de teste.
+func (c *Client) Run(ctx context.Context, req RunRequest) (EnqueueResponse, error) {
+	var response EnqueueResponse
+	err := c.do(ctx, http.MethodPost, "/run", nil, req, &response)
+	return response, err
+}
+
+// GetRun devolve o estado e a saída de uma execução criada por Run.
+func (c *Client) GetRun(ctx context.Context, token string) (Execution, error) {
+	var response Execution
+	err := c.do(ctx, http.MethodGet, "/run/"+url.PathEscape(token), nil, nil, &response)
+	return response, err
+}
+
+// ListLanguages lista as linguagens aceitas no campo language das submissões.
+func (c *Client) ListLanguages(ctx context.Context) ([]Language, error) {
+	var response struct {
+		Languages []Language `json:"languages"`
+	}
+	err := c.do(ctx, http.MethodGet, "/languages", nil, nil, &response)
+	return response.Languages, err
+}
+
+// ListProblemLanguages lista as linguagens que o problema permite, com os limites de cada uma.
+func (c *Client) ListProblemLanguages(ctx context.Context, problemID string) ([]ProblemLanguage, error) {
+	var response struct {
+		Languages []ProblemLanguage `json:"languages"`
+	}
+	err := c.do(ctx, http.MethodGet, "/problems/"+url.PathEscape(problemID)+"/languages", nil, nil, &response)
+	return response.Languages, err
+}
+
+// ListCallbacks lista as entregas de callback por status (failed, pending, delivered ou all; vazio = failed).
+func (c *Client) ListCallbacks(ctx context.Context, status string, limit int) ([]CallbackDelivery, error) {
+	query := url.Values{}
+	setIfNotEmpty(query, "status", status)
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var response struct {
+		Callbacks []CallbackDelivery `json:"callbacks"`
+	}
+	err := c.do(ctx, http.MethodGet, "/callbacks", query, nil, &response)
+	return response.Callbacks, err
+}
+
+// RetryCallback agenda um callback para ser reenviado, com as tentativas zeradas.
+func (c *Client) RetryCallback(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodPost, "/callbacks/"+strconv.FormatInt(id, 10)+"/retry", nil, nil, nil)
+}
+
+// UploadProblem instala o .zip lido de pkg como a versão atual do problema, substituindo a anterior.
+func (c *Client) UploadProblem(ctx context.Context, problemID string, pkg io.Reader) (ProblemUpload, error) {
+	header := http.Header{"Content-Type": []string{"application/zip"}}
+	resp, err := c.send(ctx, http.MethodPost, "/problems/"+url.PathEscape(problemID), nil, header, pkg, "application/json")
+	if err != nil {
+		return ProblemUpload{}, err
+	}
+	defer resp.Body.Close()
+
+	var response ProblemUpload
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return ProblemUpload{}, fmt.Errorf("judger: invalid response body: %w", err)
+	}
+	return response, nil
+}
+
+// InvalidateProblem remove o problema do cache do judger; a próxima submissão baixa o pacote da API.
+func (c *Client) InvalidateProblem(ctx context.Context, problemID string) error {
+	return c.do(ctx, http.MethodDelete, "/problems/"+url.PathEscape(problemID), nil, nil, nil)
+}
+
+func (c *Client) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (CreatedAPIKey, error) {
+	var response CreatedAPIKey
+	err := c.do(ctx, http.MethodPost, "/admin/keys", nil, req, &response)
+	return response, err
+}
+
+func (c *Client) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var response struct {
+		Keys []APIKey `json:"keys"`
+	}
+	err := c.do(ctx, http.MethodGet, "/admin/keys", nil, nil, &response)
+	return response.Keys, err
+}
+
+func (c *Client) RevokeAPIKey(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/keys/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// do envia a requisição e decodifica a resposta em out, quando não for nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	resp, err := c.send(ctx, method, path, query, nil, body, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("judger: invalid response body: %w", err)
+	}
+	return nil
+}
+
+// send monta e envia a requisição; respostas fora de 2xx viram *APIError e o corpo já é fechado.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, header http.Header, body any, accept string) (*http.Response, error) {
+	target := c.baseURL + apiPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	// um io.Reader é enviado como está, com o Content-Type vindo de header
+	reader, raw := body.(io.Reader)
+	if body != nil && !raw {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("judger: invalid request body: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("judger: invalid request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", accept)
+	if body != nil && !raw {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("judger: request failed: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, decodeError(resp)
+	}
+	return resp, nil
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	var envelope struct {
+		Error struct {
+			Code    string         `json:"code"`
+			Message string         `json:"message"`
+			Details map[string]any `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&envelope); err == nil {
+		apiErr.Code = envelope.Error.Code
+		apiErr.Message = envelope.Error.Message
+		apiErr.Details = envelope.Error.Details
+	} else {
+		apiErr.Message = resp.Status
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
+
+func setIfNotEmpty(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WatchSubmission acompanha o progresso da submissão via Server-Sent Events, chamando handle para cada
+// evento, começando pelo estado atual. Retorna nil depois do evento completed ou failed; se handle
+// devolver um erro, a conexão é encerrada e o erro é repassado.
+func (c *Client) WatchSubmission(ctx context.Context, token string, handle func(ProgressEvent) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resp, err := c.send(ctx, http.MethodGet, "/job/"+url.PathEscape(token)+"/events", nil, nil, nil, "text/event-stream")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	// um evento completed traz o relatório inteiro em uma linha
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var event ProgressEvent
+			if err := json.Unmarshal([]byte(data.String()), &event); err != nil {
+				return fmt.Errorf("judger: invalid progress event: %w", err)
+			}
+			data.Reset()
+
+			if err := handle(event); err != nil {
+				return err
+			}
+			if event.IsFinal() {
+				return nil
+			}
+
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		// comentários de keepalive (":") e o campo event são ignorados; o tipo vem no próprio JSON
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("judger: event stream interrupted: %w", err)
+	}
+	return fmt.Errorf("judger: event stream closed before a final event")
+}
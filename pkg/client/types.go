@@ -0,0 +1,251 @@
+package client
+
+import "time"
+
+// Estados de uma submissão ou execução.
+const (
+	StatusQueued     = "queued"
+	StatusProcessing = "processing"
+	StatusSuccess    = "success"
+	StatusError      = "error"
+)
+
+// Tipos de evento de progresso recebidos em WatchSubmission.
+const (
+	ProgressQueued     = "queued"
+	ProgressProcessing = "processing"
+	ProgressTestCase   = "test_case"
+	ProgressCompleted  = "completed"
+	ProgressFailed     = "failed"
+)
+
+// Tipos de evento dos callbacks.
+const (
+	EventSubmissionProcessing = "submission.processing"
+	EventSubmissionCompleted  = "submission.completed"
+	EventSubmissionFailed     = "submission.failed"
+)
+
+type SubmissionRequest struct {
+	ProblemID  string `json:"problem_id"`
+	Language   string `json:"language"`
+	Code       string `json:"code"`
+	Priority   string `json:"priority,omitempty"` // contest, practice (padrão) ou rejudge
+	WebhookURL string `json:"webhook_url,omitempty"`
+	// IdempotencyKey vai no header Idempotency-Key; reenviar a mesma submissão com a mesma chave
+	// (por exemplo, após um timeout) devolve o token original em vez de criar outra submissão
+	IdempotencyKey string `json:"-"`
+}
+
+type RunRequest struct {
+	Language   string `json:"language"`
+	Code       string `json:"code"`
+	Input      string `json:"input,omitempty"`
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+type EnqueueResponse struct {
+	Token   string `json:"token"`
+	Message string `json:"message"`
+	// Replayed indica que a Idempotency-Key já tinha sido usada e Token é o da submissão original
+	Replayed bool `json:"-"`
+}
+
+// BatchRequest é o corpo de SubmitBatch; a Idempotency-Key das submissões é ignorada.
+type BatchRequest struct {
+	Submissions []SubmissionRequest `json:"submissions"`
+}
+
+type BatchResponse struct {
+	BatchID string   `json:"batch_id"`
+	Tokens  []string `json:"tokens"` // na mesma ordem das submissões enviadas
+	Message string   `json:"message"`
+}
+
+type BatchStatus struct {
+	ID        string         `json:"id"`
+	Total     int            `json:"total"`
+	Counts    map[string]int `json:"counts"` // submissões por status
+	Done      bool           `json:"done"`
+	CreatedAt time.Time      `json:"created_at"`
+	Items     []BatchItem    `json:"items"`
+}
+
+type BatchItem struct {
+	Index     int    `json:"index"`
+	Token     string `json:"token"`
+	ProblemID string `json:"problem_id"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+type Language struct {
+	Token          string `json:"token"` // valor do campo language, ex.: "python"
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	Image          string `json:"image"`
+	FileExtension  string `json:"file_extension"`
+	CompileCommand string `json:"compile_command,omitempty"`
+	RunCommand     string `json:"run_command"`
+}
+
+type ProblemLanguage struct {
+	Language
+	TimeLimitSeconds int `json:"time_limit_seconds"`
+	MemoryLimitMB    int `json:"memory_limit_mb"`
+}
+
+type ProblemUpload struct {
+	ProblemID   string                   `json:"problem_id"`
+	Version     string                   `json:"version"`
+	SHA256      string                   `json:"sha256"`
+	TestCases   int                      `json:"test_cases"`
+	Languages   []ProblemPackageLanguage `json:"languages"`
+	InstalledAt time.Time                `json:"installed_at"`
+	Replaced    bool                     `json:"replaced"`
+	Warnings    []PackageIssue           `json:"warnings,omitempty"`
+}
+
+// PackageIssue é um problema encontrado na validação do pacote. Os erros chegam em
+// APIError.Details["issues"] no 422 invalid_package; os avisos, em ProblemUpload.Warnings.
+type PackageIssue struct {
+	Code    string `json:"code"`
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
+
+type ProblemPackageLanguage struct {
+	Language         string `json:"language"`
+	TimeLimitSeconds int    `json:"time_limit_seconds"`
+	MemoryLimitMB    int    `json:"memory_limit_mb"`
+}
+
+type Submission struct {
+	ID     string          `json:"id"`
+	Status string          `json:"status"`
+	Result ExecutionReport `json:"result"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type ExecutionReport struct {
+	Results []TestCaseResult `json:"results"`
+	Run     *RunResult       `json:"run,omitempty"`
+}
+
+type TestCaseResult struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"` // AC, WA, TLE, RTE, IER
+	TimeMS  int64  `json:"time_ms"`
+	Message string `json:"message,omitempty"`
+}
+
+type RunResult struct {
+	Status    string `json:"status"` // OK, TLE, RTE
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	Truncated bool   `json:"truncated,omitempty"`
+	ExitCode  int    `json:"exit_code"`
+	TimeMS    int64  `json:"time_ms"`
+	MemoryKB  int64  `json:"memory_kb"`
+}
+
+type Execution struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	RunStatus string `json:"run_status,omitempty"` // OK, TLE, RTE
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	Truncated bool   `json:"truncated,omitempty"`
+	ExitCode  int    `json:"exit_code"`
+	TimeMS    int64  `json:"time_ms"`
+	MemoryKB  int64  `json:"memory_kb"`
+	Error     string `json:"error"`
+}
+
+// ListSubmissionsParams são os filtros de ListSubmissions; campos vazios não filtram.
+type ListSubmissionsParams struct {
+	Status    string
+	ProblemID string
+	Language  string
+	Priority  string
+	From      time.Time
+	To        time.Time
+	Ascending bool
+	Cursor    string
+	Limit     int
+}
+
+type SubmissionSummary struct {
+	ID        string     `json:"id"`
+	ProblemID string     `json:"problem_id,omitempty"`
+	Language  string     `json:"language,omitempty"`
+	Priority  string     `json:"priority"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type SubmissionPage struct {
+	Submissions []SubmissionSummary `json:"submissions"`
+	NextCursor  string              `json:"next_cursor,omitempty"`
+}
+
+type ProgressEvent struct {
+	Type         string           `json:"type"`
+	SubmissionID string           `json:"submission_id"`
+	Status       string           `json:"status"`
+	TestCase     *TestCaseResult  `json:"test_case,omitempty"`
+	Result       *ExecutionReport `json:"result,omitempty"`
+	Error        string           `json:"error,omitempty"`
+}
+
+// IsFinal indica se o evento encerra o stream de progresso.
+func (e ProgressEvent) IsFinal() bool {
+	return e.Type == ProgressCompleted || e.Type == ProgressFailed
+}
+
+// CallbackEvent é o corpo dos callbacks enviados pelo judger; verifique a assinatura com pkg/webhook.
+type CallbackEvent struct {
+	Event        string           `json:"event"`
+	SubmissionID string           `json:"submission_id"`
+	Status       string           `json:"status"`
+	Result       *ExecutionReport `json:"result,omitempty"`
+	Error        string           `json:"error,omitempty"`
+	Timestamp    time.Time        `json:"timestamp"`
+}
+
+type CallbackDelivery struct {
+	ID            int64     `json:"id"`
+	SubmissionID  string    `json:"submission_id"`
+	URL           string    `json:"url"`
+	Status        string    `json:"status"` // pending, delivered, failed
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name               string   `json:"name"`
+	Scopes             []string `json:"scopes"` // submit, read, admin
+	RateLimitPerMinute int      `json:"rate_limit_per_minute"`
+	MaxActiveJobs      int      `json:"max_active_jobs"`
+}
+
+type APIKey struct {
+	ID                 string     `json:"id"`
+	Name               string     `json:"name"`
+	Scopes             []string   `json:"scopes"`
+	RateLimitPerMinute int        `json:"rate_limit_per_minute"`
+	MaxActiveJobs      int        `json:"max_active_jobs"`
+	CreatedAt          time.Time  `json:"created_at"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKey traz a chave em texto, que só é devolvida na criação.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -5,27 +5,57 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	APIUrl             string
-	CallbackUrl        string
-	APIKey             string
-	CacheDirectory     string
-	CacheFileExtension string
-	ExecutionDirectory string
-	RunnerBinaryPath   string
-<<<<<<< HEAD
-	DatabasePath       string
-=======
-	OnlyLocalCache     bool
->>>>>>> 478b3ab821c2dbd4891f3a3ff245f4f8f5d34585
-	ContainerTimeout   time.Duration
-	MaxWorkers         int
-	QueueSize          int
+	APIUrl                   string
+	CallbackUrl              string
+	CallbackSecret           string
+	AllowedWebhookHosts      []string
+	RunTimeLimit             time.Duration
+	RunMemoryLimitMB         int
+	RunOutputLimitKB         int
+	APIKey                   string
+	LogFormat                string
+	LogLevel                 string
+	TracesExporter           string
+	ServiceName              string
+	GRPCPort                 int
+	AuthEnabled              bool
+	BootstrapAPIKey          string
+	CacheDirectory           string
+	CacheFileExtension       string
+	ExecutionDirectory       string
+	RunnerBinaryPath         string
+	DatabasePath             string
+	OnlyLocalCache           bool
+	CacheRevalidateInterval  time.Duration
+	CacheAPITimeout          time.Duration
+	ProblemPackageMaxMB      int
+	ProblemFileMaxMB         int
+	ProblemExtractedMaxMB    int
+	ContainerTimeout         time.Duration
+	LeaseTimeout             time.Duration
+	JobMaxAttempts           int
+	IdempotencyTTL           time.Duration
+	ShutdownTimeout          time.Duration
+	ReaperInterval           time.Duration
+	MaxWorkers               int
+	QueueSize                int
+	BatchMaxSize             int
+	ReadyQueuePercent        int
+	ReadyCheckTimeout        time.Duration
+	LaneWeights              map[string]int
+	LaneReservedWorkers      map[string]int
+	CallbackMaxAttempts      int
+	CallbackProcessingEvents bool
+	CallbackBaseBackoff      time.Duration
+	CallbackMaxBackoff       time.Duration
+	CallbackTimeout          time.Duration
 }
 
 func LoadConfig() (*Config, error) {
@@ -41,7 +71,13 @@ func LoadConfig() (*Config, error) {
 	cfg := &Config{
 		APIUrl:             getEnv("API_URL", "http://localhost:4040/CasoTeste/problemaInterno"),
 		CallbackUrl:        getEnv("API_CALLBACK_URL", "http://localhost:4040/api/callbacks/judger"),
+		CallbackSecret:     getEnv("CALLBACK_SECRET", ""),
 		APIKey:             getEnv("API_KEY", "token-mega-secreto-que-ninguem-nunca-sabera-#trocarissodepoispraacessardoenv"),
+		BootstrapAPIKey:    getEnv("BOOTSTRAP_API_KEY", ""),
+		LogFormat:          getEnv("LOG_FORMAT", "text"),
+		LogLevel:           getEnv("LOG_LEVEL", "info"),
+		TracesExporter:     getEnv("OTEL_TRACES_EXPORTER", "none"),
+		ServiceName:        getEnv("OTEL_SERVICE_NAME", "ifjudger"),
 		CacheDirectory:     getEnvPath("CACHE_DIRECTORY", baseDir, "internal/api/cache"),
 		CacheFileExtension: getEnv("CACHE_FILEEXTENSION", "-problem"),
 		ExecutionDirectory: getEnvPath("EXECUTION_DIRECTORY", baseDir, "internal/api/cache/executions"),
@@ -49,24 +85,195 @@ func LoadConfig() (*Config, error) {
 		DatabasePath:       getEnvPath("DATABASE_PATH", baseDir, "judger.db"),
 	}
 
-	var err error
-
 	seconds, err := strconv.Atoi(getEnv("CONTAINER_TIMEOUT_SECONDS", "600"))
 	if err != nil {
 		return nil, fmt.Errorf("erro ao ler CONTAINER_TIMEOUT_SECONDS: %w", err)
 	}
 	cfg.ContainerTimeout = time.Duration(seconds) * time.Second
 
+	leaseSeconds, err := strconv.Atoi(getEnv("LEASE_TIMEOUT_SECONDS", "60"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler LEASE_TIMEOUT_SECONDS: %w", err)
+	}
+	if leaseSeconds <= 0 {
+		return nil, fmt.Errorf("LEASE_TIMEOUT_SECONDS deve ser maior que zero")
+	}
+	cfg.LeaseTimeout = time.Duration(leaseSeconds) * time.Second
+
+	cfg.JobMaxAttempts, err = strconv.Atoi(getEnv("JOB_MAX_ATTEMPTS", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler JOB_MAX_ATTEMPTS: %w", err)
+	}
+	if cfg.JobMaxAttempts <= 0 {
+		return nil, fmt.Errorf("JOB_MAX_ATTEMPTS deve ser maior que zero")
+	}
+
+	idempotencyHours, err := strconv.Atoi(getEnv("IDEMPOTENCY_TTL_HOURS", "24"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler IDEMPOTENCY_TTL_HOURS: %w", err)
+	}
+	if idempotencyHours <= 0 {
+		return nil, fmt.Errorf("IDEMPOTENCY_TTL_HOURS deve ser maior que zero")
+	}
+	cfg.IdempotencyTTL = time.Duration(idempotencyHours) * time.Hour
+
+	shutdownSeconds, err := strconv.Atoi(getEnv("SHUTDOWN_TIMEOUT_SECONDS", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler SHUTDOWN_TIMEOUT_SECONDS: %w", err)
+	}
+	cfg.ShutdownTimeout = time.Duration(shutdownSeconds) * time.Second
+
+	reaperSeconds, err := strconv.Atoi(getEnv("REAPER_INTERVAL_SECONDS", "300"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler REAPER_INTERVAL_SECONDS: %w", err)
+	}
+	cfg.ReaperInterval = time.Duration(reaperSeconds) * time.Second
+
 	cfg.MaxWorkers, err = strconv.Atoi(getEnv("MAX_WORKERS", "3"))
 	if err != nil {
 		return nil, fmt.Errorf("erro ao ler MAX_WORKERS: %w", err)
 	}
 
-	cfg.QueueSize, err = strconv.Atoi(getEnv("QUEUE_SIZE", "10"))
+	cfg.QueueSize, err = strconv.Atoi(getEnv("QUEUE_SIZE", "500"))
 	if err != nil {
 		return nil, fmt.Errorf("erro ao ler QUEUE_SIZE: %w", err)
 	}
 
+	cfg.BatchMaxSize, err = strconv.Atoi(getEnv("BATCH_MAX_SIZE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler BATCH_MAX_SIZE: %w", err)
+	}
+	if cfg.BatchMaxSize <= 0 {
+		return nil, fmt.Errorf("BATCH_MAX_SIZE deve ser maior que zero")
+	}
+
+	cfg.ReadyQueuePercent, err = strconv.Atoi(getEnv("READY_QUEUE_THRESHOLD_PERCENT", "90"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler READY_QUEUE_THRESHOLD_PERCENT: %w", err)
+	}
+
+	readyTimeoutSeconds, err := strconv.Atoi(getEnv("READY_CHECK_TIMEOUT_SECONDS", "2"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler READY_CHECK_TIMEOUT_SECONDS: %w", err)
+	}
+	cfg.ReadyCheckTimeout = time.Duration(readyTimeoutSeconds) * time.Second
+
+	cfg.LaneWeights, err = parseLaneMap(getEnv("PRIORITY_WEIGHTS", "contest:6,practice:3,run:3,rejudge:1"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PRIORITY_WEIGHTS: %w", err)
+	}
+
+	cfg.LaneReservedWorkers, err = parseLaneMap(getEnv("PRIORITY_RESERVED_WORKERS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PRIORITY_RESERVED_WORKERS: %w", err)
+	}
+
+	cfg.CallbackMaxAttempts, err = strconv.Atoi(getEnv("CALLBACK_MAX_ATTEMPTS", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CALLBACK_MAX_ATTEMPTS: %w", err)
+	}
+
+	backoffSeconds, err := strconv.Atoi(getEnv("CALLBACK_BASE_BACKOFF_SECONDS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CALLBACK_BASE_BACKOFF_SECONDS: %w", err)
+	}
+	cfg.CallbackBaseBackoff = time.Duration(backoffSeconds) * time.Second
+
+	maxBackoffSeconds, err := strconv.Atoi(getEnv("CALLBACK_MAX_BACKOFF_SECONDS", "3600"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CALLBACK_MAX_BACKOFF_SECONDS: %w", err)
+	}
+	cfg.CallbackMaxBackoff = time.Duration(maxBackoffSeconds) * time.Second
+
+	callbackTimeoutSeconds, err := strconv.Atoi(getEnv("CALLBACK_TIMEOUT_SECONDS", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CALLBACK_TIMEOUT_SECONDS: %w", err)
+	}
+	cfg.CallbackTimeout = time.Duration(callbackTimeoutSeconds) * time.Second
+
+	cfg.CallbackProcessingEvents, err = strconv.ParseBool(getEnv("CALLBACK_PROCESSING_EVENTS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CALLBACK_PROCESSING_EVENTS: %w", err)
+	}
+
+	runSeconds, err := strconv.Atoi(getEnv("RUN_TIME_LIMIT_SECONDS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler RUN_TIME_LIMIT_SECONDS: %w", err)
+	}
+	cfg.RunTimeLimit = time.Duration(runSeconds) * time.Second
+
+	cfg.RunMemoryLimitMB, err = strconv.Atoi(getEnv("RUN_MEMORY_LIMIT_MB", "256"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler RUN_MEMORY_LIMIT_MB: %w", err)
+	}
+
+	cfg.RunOutputLimitKB, err = strconv.Atoi(getEnv("RUN_OUTPUT_LIMIT_KB", "64"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler RUN_OUTPUT_LIMIT_KB: %w", err)
+	}
+
+	cfg.GRPCPort, err = strconv.Atoi(getEnv("GRPC_PORT", "9090"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler GRPC_PORT: %w", err)
+	}
+	if cfg.GRPCPort < 0 || cfg.GRPCPort > 65535 {
+		return nil, fmt.Errorf("GRPC_PORT deve estar entre 0 e 65535")
+	}
+
+	cfg.AllowedWebhookHosts = parseList(getEnv("WEBHOOK_ALLOWED_HOSTS", ""))
+
+	cfg.AuthEnabled, err = strconv.ParseBool(getEnv("AUTH_ENABLED", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler AUTH_ENABLED: %w", err)
+	}
+
+	cfg.ProblemPackageMaxMB, err = strconv.Atoi(getEnv("PROBLEM_PACKAGE_MAX_MB", "64"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PROBLEM_PACKAGE_MAX_MB: %w", err)
+	}
+	if cfg.ProblemPackageMaxMB <= 0 {
+		return nil, fmt.Errorf("PROBLEM_PACKAGE_MAX_MB deve ser maior que zero")
+	}
+
+	cfg.ProblemFileMaxMB, err = strconv.Atoi(getEnv("PROBLEM_FILE_MAX_MB", "32"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PROBLEM_FILE_MAX_MB: %w", err)
+	}
+	if cfg.ProblemFileMaxMB <= 0 {
+		return nil, fmt.Errorf("PROBLEM_FILE_MAX_MB deve ser maior que zero")
+	}
+
+	cfg.ProblemExtractedMaxMB, err = strconv.Atoi(getEnv("PROBLEM_EXTRACTED_MAX_MB", "256"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PROBLEM_EXTRACTED_MAX_MB: %w", err)
+	}
+	if cfg.ProblemExtractedMaxMB <= 0 {
+		return nil, fmt.Errorf("PROBLEM_EXTRACTED_MAX_MB deve ser maior que zero")
+	}
+
+	revalidateSeconds, err := strconv.Atoi(getEnv("CACHE_REVALIDATE_SECONDS", "300"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CACHE_REVALIDATE_SECONDS: %w", err)
+	}
+	if revalidateSeconds < 0 {
+		return nil, fmt.Errorf("CACHE_REVALIDATE_SECONDS não pode ser negativo")
+	}
+	cfg.CacheRevalidateInterval = time.Duration(revalidateSeconds) * time.Second
+
+	apiTimeoutSeconds, err := strconv.Atoi(getEnv("CACHE_API_TIMEOUT_SECONDS", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CACHE_API_TIMEOUT_SECONDS: %w", err)
+	}
+	if apiTimeoutSeconds <= 0 {
+		return nil, fmt.Errorf("CACHE_API_TIMEOUT_SECONDS deve ser maior que zero")
+	}
+	cfg.CacheAPITimeout = time.Duration(apiTimeoutSeconds) * time.Second
+
+	cfg.OnlyLocalCache, err = strconv.ParseBool(getEnv("ONLY_LOCAL_CACHE", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler ONLY_LOCAL_CACHE: %w", err)
+	}
+
 	return cfg, nil
 }
 
@@ -77,6 +284,40 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// parseList lê listas separadas por vírgula, ignorando entradas vazias
+func parseList(raw string) []string {
+	var values []string
+	for _, entry := range strings.Split(raw, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			values = append(values, entry)
+		}
+	}
+	return values
+}
+
+// parseLaneMap lê listas no formato "lane:valor,lane:valor"
+func parseLaneMap(raw string) (map[string]int, error) {
+	values := make(map[string]int)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		lane, valueStr, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("entrada inválida %q, esperado lane:valor", entry)
+		}
+
+		value, err := strconv.Atoi(strings.TrimSpace(valueStr))
+		if err != nil {
+			return nil, fmt.Errorf("valor inválido para %s: %w", lane, err)
+		}
+		values[strings.TrimSpace(lane)] = value
+	}
+	return values, nil
+}
+
 // getEnvPath obtém uma variável de ambiente ou retorna um caminho relativo ao baseDir
 func getEnvPath(key, baseDir, relativePath string) string {
 	if value, exists := os.LookupEnv(key); exists {
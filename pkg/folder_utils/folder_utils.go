@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -101,13 +102,31 @@ func CopyFile(src, dst string) error {
 	return os.Chmod(dst, info.Mode())
 }
 
-func Unzip(src, dest string) error {
+// LimitError indica que a extração passou de um limite do Unzip. Name é o arquivo em que o limite
+// por arquivo estourou; vazio quando foi o total extraído.
+type LimitError struct {
+	Name  string
+	Limit int64
+}
+
+func (e *LimitError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("extracted content exceeds %d bytes", e.Limit)
+	}
+	return fmt.Sprintf("%s has more than %d bytes", e.Name, e.Limit)
+}
+
+// Unzip extrai src em dest. maxFileBytes limita cada arquivo e maxTotalBytes a soma deles (0 não
+// limita): o tamanho declarado no .zip é conferido antes de extrair e a cópia para no limite mesmo
+// que o cabeçalho minta, devolvendo *LimitError.
+func Unzip(src, dest string, maxFileBytes, maxTotalBytes int64) error {
 	r, err := zip.OpenReader(src)
 	if err != nil {
 		return err
 	}
 	defer r.Close()
 
+	var total int64
 	for _, f := range r.File {
 		fpath := filepath.Join(dest, f.Name)
 		if !strings.HasPrefix(fpath, filepath.Clean(dest)+string(os.PathSeparator)) {
@@ -119,6 +138,18 @@ func Unzip(src, dest string) error {
 			continue
 		}
 
+		// quanto este arquivo ainda pode ocupar, pelo limite dele e pelo que resta do total
+		remaining := int64(math.MaxInt64 - 1) // +1 na cópia detecta o excesso sem estourar o int64
+		if maxFileBytes > 0 {
+			remaining = maxFileBytes
+		}
+		if maxTotalBytes > 0 {
+			remaining = min(remaining, maxTotalBytes-total)
+		}
+		if err := checkLimit(f.Name, f.UncompressedSize64, total, maxFileBytes, maxTotalBytes); err != nil {
+			return err
+		}
+
 		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
 			return err
 		}
@@ -134,7 +165,7 @@ func Unzip(src, dest string) error {
 			return err
 		}
 
-		_, err = io.Copy(outFile, rc)
+		written, err := io.Copy(outFile, io.LimitReader(rc, remaining+1))
 
 		outFile.Close()
 		rc.Close()
@@ -142,6 +173,20 @@ func Unzip(src, dest string) error {
 		if err != nil {
 			return err
 		}
+		if written > remaining {
+			return checkLimit(f.Name, uint64(written), total, maxFileBytes, maxTotalBytes)
+		}
+		total += written
+	}
+	return nil
+}
+
+func checkLimit(name string, size uint64, total, maxFileBytes, maxTotalBytes int64) error {
+	if maxFileBytes > 0 && size > uint64(maxFileBytes) {
+		return &LimitError{Name: name, Limit: maxFileBytes}
+	}
+	if maxTotalBytes > 0 && size > uint64(maxTotalBytes-total) {
+		return &LimitError{Limit: maxTotalBytes}
 	}
 	return nil
 }
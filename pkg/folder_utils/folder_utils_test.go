@@ -0,0 +1,199 @@
+package folderutils_test
+
+import (
+	folderutils "IFJudger/pkg/folder_utils"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"errors"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const kb = 1 << 10
+
+// zipEntry é um arquivo do .zip de teste. Com declaredSize, o cabeçalho informa esse tamanho em
+// vez do real, como num .zip montado para enganar a conferência prévia.
+type zipEntry struct {
+	name         string
+	content      []byte
+	declaredSize uint64
+}
+
+// writeZip monta o .zip em memória e o grava no diretório temporário do teste.
+func writeZip(t *testing.T, entries ...zipEntry) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		if entry.declaredSize == 0 {
+			w, err := zw.Create(entry.name)
+			if err != nil {
+				t.Fatalf("criar %s: %v", entry.name, err)
+			}
+			if _, err := w.Write(entry.content); err != nil {
+				t.Fatalf("escrever %s: %v", entry.name, err)
+			}
+			continue
+		}
+
+		var compressed bytes.Buffer
+		fw, err := flate.NewWriter(&compressed, flate.BestCompression)
+		if err != nil {
+			t.Fatalf("flate: %v", err)
+		}
+		fw.Write(entry.content)
+		fw.Close()
+
+		w, err := zw.CreateRaw(&zip.FileHeader{
+			Name:               entry.name,
+			Method:             zip.Deflate,
+			CRC32:              crc32.ChecksumIEEE(entry.content),
+			CompressedSize64:   uint64(compressed.Len()),
+			UncompressedSize64: entry.declaredSize,
+		})
+		if err != nil {
+			t.Fatalf("criar %s: %v", entry.name, err)
+		}
+		if _, err := w.Write(compressed.Bytes()); err != nil {
+			t.Fatalf("escrever %s: %v", entry.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("fechar zip: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "pacote.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("gravar zip: %v", err)
+	}
+	return path
+}
+
+// extractedBytes soma o que a extração deixou em dir.
+func extractedBytes(t *testing.T, dir string) int64 {
+	t.Helper()
+
+	var total int64
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ler extração: %v", err)
+	}
+	return total
+}
+
+func TestUnzipLimits(t *testing.T) {
+	zeros := func(n int) []byte { return make([]byte, n) }
+
+	tests := []struct {
+		name    string
+		entries []zipEntry
+		// nome esperado no *LimitError; vazio para o limite total
+		wantName string
+	}{
+		{
+			name:     "arquivo acima do limite",
+			entries:  []zipEntry{{name: "1.in", content: []byte("1\n")}, {name: "2.in", content: zeros(200 * kb)}},
+			wantName: "2.in",
+		},
+		{
+			// arquivos pequenos, muito compressíveis, que juntos passam do total
+			name: "zip bomb",
+			entries: []zipEntry{
+				{name: "1.in", content: zeros(90 * kb)},
+				{name: "2.in", content: zeros(90 * kb)},
+				{name: "3.in", content: zeros(90 * kb)},
+			},
+		},
+		{
+			name:     "subdiretório conta no limite",
+			entries:  []zipEntry{{name: "extra/grande.bin", content: zeros(101 * kb)}},
+			wantName: "extra/grande.bin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := t.TempDir()
+			err := folderutils.Unzip(writeZip(t, tt.entries...), dest, 100*kb, 200*kb)
+
+			var limitErr *folderutils.LimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("Unzip = %v, esperado *LimitError", err)
+			}
+			if limitErr.Name != tt.wantName {
+				t.Errorf("LimitError.Name = %q, esperado %q", limitErr.Name, tt.wantName)
+			}
+			if written := extractedBytes(t, dest); written > 200*kb {
+				t.Errorf("extraídos %d bytes, acima do limite total", written)
+			}
+		})
+	}
+}
+
+// TestUnzipUnderstatedSize confere que um cabeçalho que declara menos bytes do que o arquivo tem
+// não passa dos limites: a extração falha sem gravar além do que o cabeçalho (ou o limite) permite.
+func TestUnzipUnderstatedSize(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry zipEntry
+	}{
+		{name: "arquivo de 1 MB declarado com 10 bytes", entry: zipEntry{name: "1.in", content: make([]byte, 1<<20), declaredSize: 10}},
+		{name: "arquivo de 1 MB declarado no limite", entry: zipEntry{name: "1.in", content: make([]byte, 1<<20), declaredSize: 100 * kb}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := t.TempDir()
+			err := folderutils.Unzip(writeZip(t, tt.entry), dest, 100*kb, 200*kb)
+			if err == nil {
+				t.Fatal("Unzip aceitou o arquivo com o tamanho declarado menor que o real")
+			}
+			if written := extractedBytes(t, dest); written > 100*kb {
+				t.Errorf("extraídos %d bytes, acima do limite por arquivo", written)
+			}
+		})
+	}
+}
+
+func TestUnzipWithinLimits(t *testing.T) {
+	dest := t.TempDir()
+	path := writeZip(t,
+		zipEntry{name: "meta.json", content: []byte(`[{"name":"python"}]`)},
+		zipEntry{name: "1.in", content: bytes.Repeat([]byte("1 2\n"), 1000)},
+		zipEntry{name: "1.out", content: []byte("3\n")},
+	)
+
+	if err := folderutils.Unzip(path, dest, 100*kb, 200*kb); err != nil {
+		t.Fatalf("Unzip: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dest, "1.in"))
+	if err != nil || len(content) != 4000 {
+		t.Errorf("1.in extraído com %d bytes (err %v), esperado 4000", len(content), err)
+	}
+}
+
+func TestUnzipRejectsPathTraversal(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "pacote")
+	err := folderutils.Unzip(writeZip(t, zipEntry{name: "../fora.in", content: []byte("x")}), dest, 100*kb, 200*kb)
+	if err == nil {
+		t.Fatal("Unzip aceitou um caminho fora do destino")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "fora.in")); !os.IsNotExist(err) {
+		t.Errorf("arquivo gravado fora do destino (stat: %v)", err)
+	}
+}
@@ -0,0 +1,5 @@
+// Package judgerpb contém as mensagens e o cliente/servidor gRPC gerados a partir de judger.proto.
+// Serviços internos podem usá-lo direto: judgerpb.NewJudgerClient(conn).
+package judgerpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative judger.proto
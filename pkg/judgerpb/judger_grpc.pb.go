@@ -0,0 +1,300 @@
+// API gRPC do judger, equivalente às rotas de submissão da API HTTP (/v1).
+// Gere o código Go com `go generate ./pkg/judgerpb` (requer protoc, protoc-gen-go e protoc-gen-go-grpc).
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: judger.proto
+
+package judgerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Judger_Submit_FullMethodName          = "/judger.v1.Judger/Submit"
+	Judger_GetStatus_FullMethodName       = "/judger.v1.Judger/GetStatus"
+	Judger_WatchSubmission_FullMethodName = "/judger.v1.Judger/WatchSubmission"
+	Judger_Cancel_FullMethodName          = "/judger.v1.Judger/Cancel"
+	Judger_ListLanguages_FullMethodName   = "/judger.v1.Judger/ListLanguages"
+)
+
+// JudgerClient is the client API for Judger service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Judger corrige submissões contra os casos de teste dos problemas. Com AUTH_ENABLED=true,
+// toda chamada precisa do metadata "authorization: Bearer <chave>" (ou "x-api-key").
+type JudgerClient interface {
+	// Submit enfileira uma submissão para correção (escopo submit), como POST /v1/submit.
+	Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
+	// GetStatus devolve o estado e, quando terminada, o resultado da submissão (escopo read).
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Submission, error)
+	// WatchSubmission transmite o progresso da submissão, começando pelo estado atual,
+	// até o evento completed ou failed (escopo read).
+	WatchSubmission(ctx context.Context, in *WatchSubmissionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProgressEvent], error)
+	// Cancel cancela uma submissão que ainda está na fila (escopo submit). Ela termina com
+	// status error e gera o callback submission.failed; submissões já em execução não podem ser canceladas.
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*Submission, error)
+	// ListLanguages lista as linguagens aceitas no campo language (escopo read).
+	ListLanguages(ctx context.Context, in *ListLanguagesRequest, opts ...grpc.CallOption) (*ListLanguagesResponse, error)
+}
+
+type judgerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJudgerClient(cc grpc.ClientConnInterface) JudgerClient {
+	return &judgerClient{cc}
+}
+
+func (c *judgerClient) Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitResponse)
+	err := c.cc.Invoke(ctx, Judger_Submit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *judgerClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Submission, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Submission)
+	err := c.cc.Invoke(ctx, Judger_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *judgerClient) WatchSubmission(ctx context.Context, in *WatchSubmissionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProgressEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Judger_ServiceDesc.Streams[0], Judger_WatchSubmission_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchSubmissionRequest, ProgressEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Judger_WatchSubmissionClient = grpc.ServerStreamingClient[ProgressEvent]
+
+func (c *judgerClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*Submission, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Submission)
+	err := c.cc.Invoke(ctx, Judger_Cancel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *judgerClient) ListLanguages(ctx context.Context, in *ListLanguagesRequest, opts ...grpc.CallOption) (*ListLanguagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLanguagesResponse)
+	err := c.cc.Invoke(ctx, Judger_ListLanguages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// JudgerServer is the server API for Judger service.
+// All implementations must embed UnimplementedJudgerServer
+// for forward compatibility.
+//
+// Judger corrige submissões contra os casos de teste dos problemas. Com AUTH_ENABLED=true,
+// toda chamada precisa do metadata "authorization: Bearer <chave>" (ou "x-api-key").
+type JudgerServer interface {
+	// Submit enfileira uma submissão para correção (escopo submit), como POST /v1/submit.
+	Submit(context.Context, *SubmitRequest) (*SubmitResponse, error)
+	// GetStatus devolve o estado e, quando terminada, o resultado da submissão (escopo read).
+	GetStatus(context.Context, *GetStatusRequest) (*Submission, error)
+	// WatchSubmission transmite o progresso da submissão, começando pelo estado atual,
+	// até o evento completed ou failed (escopo read).
+	WatchSubmission(*WatchSubmissionRequest, grpc.ServerStreamingServer[ProgressEvent]) error
+	// Cancel cancela uma submissão que ainda está na fila (escopo submit). Ela termina com
+	// status error e gera o callback submission.failed; submissões já em execução não podem ser canceladas.
+	Cancel(context.Context, *CancelRequest) (*Submission, error)
+	// ListLanguages lista as linguagens aceitas no campo language (escopo read).
+	ListLanguages(context.Context, *ListLanguagesRequest) (*ListLanguagesResponse, error)
+	mustEmbedUnimplementedJudgerServer()
+}
+
+// UnimplementedJudgerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedJudgerServer struct{}
+
+func (UnimplementedJudgerServer) Submit(context.Context, *SubmitRequest) (*SubmitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Submit not implemented")
+}
+func (UnimplementedJudgerServer) GetStatus(context.Context, *GetStatusRequest) (*Submission, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedJudgerServer) WatchSubmission(*WatchSubmissionRequest, grpc.ServerStreamingServer[ProgressEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSubmission not implemented")
+}
+func (UnimplementedJudgerServer) Cancel(context.Context, *CancelRequest) (*Submission, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+func (UnimplementedJudgerServer) ListLanguages(context.Context, *ListLanguagesRequest) (*ListLanguagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLanguages not implemented")
+}
+func (UnimplementedJudgerServer) mustEmbedUnimplementedJudgerServer() {}
+func (UnimplementedJudgerServer) testEmbeddedByValue()                {}
+
+// UnsafeJudgerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to JudgerServer will
+// result in compilation errors.
+type UnsafeJudgerServer interface {
+	mustEmbedUnimplementedJudgerServer()
+}
+
+func RegisterJudgerServer(s grpc.ServiceRegistrar, srv JudgerServer) {
+	// If the following call pancis, it indicates UnimplementedJudgerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Judger_ServiceDesc, srv)
+}
+
+func _Judger_Submit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JudgerServer).Submit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Judger_Submit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JudgerServer).Submit(ctx, req.(*SubmitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Judger_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JudgerServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Judger_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JudgerServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Judger_WatchSubmission_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSubmissionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JudgerServer).WatchSubmission(m, &grpc.GenericServerStream[WatchSubmissionRequest, ProgressEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Judger_WatchSubmissionServer = grpc.ServerStreamingServer[ProgressEvent]
+
+func _Judger_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JudgerServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Judger_Cancel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JudgerServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Judger_ListLanguages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLanguagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JudgerServer).ListLanguages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Judger_ListLanguages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JudgerServer).ListLanguages(ctx, req.(*ListLanguagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Judger_ServiceDesc is the grpc.ServiceDesc for Judger service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Judger_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "judger.v1.Judger",
+	HandlerType: (*JudgerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Submit",
+			Handler:    _Judger_Submit_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _Judger_GetStatus_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Judger_Cancel_Handler,
+		},
+		{
+			MethodName: "ListLanguages",
+			Handler:    _Judger_ListLanguages_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchSubmission",
+			Handler:       _Judger_WatchSubmission_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "judger.proto",
+}
@@ -0,0 +1,54 @@
+// Package logger configura o log/slog do judger e define as chaves de atributos
+// compartilhadas, para que uma submissão possa ser seguida entre os serviços.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Chaves de atributos usadas em todos os logs.
+const (
+	KeyComponent   = "component"
+	KeyJobID       = "job_id"
+	KeyProblemID   = "problem_id"
+	KeyWorkerID    = "worker_id"
+	KeyContainerID = "container_id"
+	KeyError       = "error"
+)
+
+// Setup troca o logger padrão por um handler text ou json no nível pedido.
+// Logs feitos com o pacote log também passam a sair por ele.
+func Setup(output io.Writer, format, level string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+
+	options := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		handler = slog.NewJSONHandler(output, options)
+	case "text", "":
+		handler = slog.NewTextHandler(output, options)
+	default:
+		return fmt.Errorf("invalid log format %q, expected text or json", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// Component retorna o logger padrão marcado com o componente que está logando.
+func Component(name string) *slog.Logger {
+	return slog.Default().With(KeyComponent, name)
+}
+
+// Err padroniza o atributo de erro.
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
@@ -0,0 +1,119 @@
+// Package metrics reúne as métricas Prometheus do judger, expostas em GET /metrics.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "judger"
+
+// Etapas de um job medidas em JobStageDuration.
+const (
+	StageQueueWait     = "queue_wait"
+	StageWorkspacePrep = "workspace_prep"
+	StageContainer     = "container"
+	StageTotal         = "total"
+)
+
+// Resultados de uma consulta ao cache de problemas.
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// Resultados da revalidação de um pacote do cache com a API.
+const (
+	RevalidationNotModified = "not_modified"
+	RevalidationUpdated     = "updated"
+	RevalidationError       = "error"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	ActiveWorkers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_workers",
+		Help:      "Workers executando um job neste momento.",
+	})
+
+	Workers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "workers",
+		Help:      "Workers configurados na instância.",
+	})
+
+	JobStageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_stage_duration_seconds",
+		Help:      "Duração de cada etapa de um job: espera na fila, preparo do workspace, container e total.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"stage"})
+
+	Verdicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "verdicts_total",
+		Help:      "Veredictos finais das submissões por linguagem e problema.",
+	}, []string{"language", "problem_id", "verdict"})
+
+	CacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Consultas ao cache de problemas, por resultado (hit ou miss).",
+	}, []string{"result"})
+
+	CacheRevalidations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_revalidations_total",
+		Help:      "Revalidações de pacotes do cache com a API, por resultado (not_modified, updated ou error).",
+	}, []string{"result"})
+
+	CacheDownloadDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "cache_download_duration_seconds",
+		Help:      "Duração do download e extração de pacotes de problemas ausentes no cache.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+	}, []string{"outcome"})
+
+	CallbackFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "callback_failures_total",
+		Help:      "Entregas de callback que falharam, por desfecho (retry ou failed, quando as tentativas acabam).",
+	}, []string{"outcome"})
+
+	DockerErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "docker_errors_total",
+		Help:      "Erros retornados pelo daemon do Docker, por operação.",
+	}, []string{"operation"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		ActiveWorkers,
+		Workers,
+		JobStageDuration,
+		Verdicts,
+		CacheLookups,
+		CacheDownloadDuration,
+		CacheRevalidations,
+		CallbackFailures,
+		DockerErrors,
+	)
+}
+
+// Register adiciona coletores que calculam seus valores na hora do scrape, como a profundidade da fila.
+func Register(collector prometheus.Collector) error {
+	return registry.Register(collector)
+}
+
+// Handler serve as métricas no formato de exposição do Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
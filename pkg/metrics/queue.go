@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var queueDepthDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "queue_depth"),
+	"Jobs aguardando um worker, por lane de prioridade.",
+	[]string{"lane"}, nil,
+)
+
+// QueueCollector consulta a profundidade da fila a cada scrape, em vez de manter um gauge
+// sincronizado com o banco, que pode ser compartilhado entre instâncias.
+type QueueCollector struct {
+	depth func() (map[string]int, error)
+}
+
+func NewQueueCollector(depth func() (map[string]int, error)) *QueueCollector {
+	return &QueueCollector{depth: depth}
+}
+
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+}
+
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	depth, err := c.depth()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(queueDepthDesc, err)
+		return
+	}
+
+	for lane, count := range depth {
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(count), lane)
+	}
+}
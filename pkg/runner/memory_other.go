@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "os"
+
+// peakMemoryKB não é suportado fora do Linux; o runner sempre executa dentro do container.
+func peakMemoryKB(state *os.ProcessState) int64 {
+	return 0
+}
@@ -15,8 +15,23 @@ import (
 
 type ExecutionReport struct {
 	Results []TestCaseResult `json:"results"`
+	Run     *RunResult       `json:"run,omitempty"`
 }
 
+// RunResult é o resultado do modo run: uma execução com a entrada do usuário, sem comparação de saída.
+type RunResult struct {
+	Status    string `json:"status"` // OK, TLE, RTE
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	Truncated bool   `json:"truncated,omitempty"`
+	ExitCode  int    `json:"exit_code"`
+	TimeMS    int64  `json:"time_ms"`
+	MemoryKB  int64  `json:"memory_kb"`
+}
+
+// arquivo de entrada usado no modo run
+const runInputFile = "run.in"
+
 type TestCaseResult struct {
 	ID      string `json:"id"`
 	Status  string `json:"status"`
@@ -24,8 +39,32 @@ type TestCaseResult struct {
 	Message string `json:"message,omitempty"`
 }
 
+// ProgressEvent é escrito no stdout do runner, uma linha JSON por caso de teste,
+// para que o worker acompanhe a execução enquanto o container roda.
+type ProgressEvent struct {
+	Type   string         `json:"type"`
+	Result TestCaseResult `json:"result"`
+}
+
+type runnerArgs struct {
+	cmd         []string
+	timeout     time.Duration
+	mode        string
+	outputLimit int
+}
+
 func main() {
-	userCmd, timeout := parseArgs()
+	args := parseArgs()
+	userCmd, timeout := args.cmd, args.timeout
+
+	if args.mode == "run" {
+		report := ExecutionReport{Results: []TestCaseResult{}, Run: runOnce(userCmd, timeout, args.outputLimit)}
+		if err := saveReport(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Falha ao salvar relatório: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	inputs, err := findTestInputs(".")
 	if err != nil {
@@ -37,6 +76,7 @@ func main() {
 	for _, inputDesc := range inputs {
 		res := runTestCase(inputDesc, userCmd, timeout)
 		results = append(results, res)
+		emitProgress(res)
 	}
 
 	report := ExecutionReport{Results: results}
@@ -52,27 +92,36 @@ type TestPair struct {
 	OutputPath string
 }
 
-func parseArgs() ([]string, time.Duration) {
-	var cmd []string
-	var timeout time.Duration = 2 * time.Second
+func parseArgs() runnerArgs {
+	args := runnerArgs{
+		timeout:     2 * time.Second,
+		mode:        "judge",
+		outputLimit: 64 * 1024,
+	}
 
 	for _, arg := range os.Args[1:] {
 		if strings.HasPrefix(arg, "--testTimeout=") {
 			valStr := strings.TrimPrefix(arg, "--testTimeout=")
 			if val, err := strconv.ParseInt(valStr, 10, 64); err == nil {
-				timeout = time.Duration(val)
+				args.timeout = time.Duration(val)
+			}
+		} else if strings.HasPrefix(arg, "--mode=") {
+			args.mode = strings.TrimPrefix(arg, "--mode=")
+		} else if strings.HasPrefix(arg, "--outputLimit=") {
+			if val, err := strconv.Atoi(strings.TrimPrefix(arg, "--outputLimit=")); err == nil && val > 0 {
+				args.outputLimit = val
 			}
 		} else {
-			cmd = append(cmd, arg)
+			args.cmd = append(args.cmd, arg)
 		}
 	}
 
-	if len(cmd) == 0 {
+	if len(args.cmd) == 0 {
 		fmt.Println("Nenhum comando fornecido")
 		os.Exit(1)
 	}
 
-	return cmd, timeout
+	return args
 }
 
 func findTestInputs(dir string) ([]TestPair, error) {
@@ -157,6 +206,87 @@ func runTestCase(test TestPair, cmdArgs []string, timeout time.Duration) TestCas
 	return result
 }
 
+// runOnce executa o programa uma vez com run.in como entrada, guardando no máximo outputLimit bytes de stdout e stderr.
+func runOnce(cmdArgs []string, timeout time.Duration, outputLimit int) *RunResult {
+	result := &RunResult{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, cmdArgs[0], cmdArgs[1:]...)
+
+	inputFile, err := os.Open(runInputFile)
+	if err != nil {
+		result.Status = "IER"
+		result.Stderr = fmt.Sprintf("Failed to open input: %v", err)
+		return result
+	}
+	defer inputFile.Close()
+	cmd.Stdin = inputFile
+
+	stdout := &cappedBuffer{limit: outputLimit}
+	stderr := &cappedBuffer{limit: outputLimit}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	start := time.Now()
+	err = cmd.Run()
+	result.TimeMS = time.Since(start).Milliseconds()
+
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	result.Truncated = stdout.truncated || stderr.truncated
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+		result.MemoryKB = peakMemoryKB(cmd.ProcessState)
+	}
+
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		result.Status = "TLE"
+	case err != nil:
+		result.Status = "RTE"
+	default:
+		result.Status = "OK"
+	}
+
+	return result
+}
+
+// cappedBuffer guarda até limit bytes e descarta o resto, sem interromper o programa.
+// O buffer não é embutido para que io.Copy não use bytes.Buffer.ReadFrom e ignore o limite.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - b.buf.Len()
+	if remaining <= 0 {
+		b.truncated = b.truncated || len(p) > 0
+		return len(p), nil
+	}
+	if len(p) > remaining {
+		b.buf.Write(p[:remaining])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
+
+func emitProgress(res TestCaseResult) {
+	line, err := json.Marshal(ProgressEvent{Type: "test_case", Result: res})
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(os.Stdout, string(line))
+}
+
 func normalizeString(s string) string {
 	return strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
 }
@@ -0,0 +1,101 @@
+// Package webhook assina e verifica os callbacks enviados pelo judger.
+//
+// A assinatura é um HMAC-SHA256 de "<timestamp>.<corpo>" com o segredo compartilhado
+// (CALLBACK_SECRET), enviada nos headers X-Judger-Timestamp e X-Judger-Signature.
+// Incluir o timestamp na assinatura permite ao receptor recusar callbacks reenviados
+// por terceiros fora da janela de tolerância.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TimestampHeader = "X-Judger-Timestamp"
+	SignatureHeader = "X-Judger-Signature"
+
+	signaturePrefix = "sha256="
+
+	// DefaultTolerance é a diferença máxima aceita entre o timestamp do callback e o relógio do receptor.
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrMissingHeaders   = errors.New("missing signature headers")
+	ErrInvalidTimestamp = errors.New("invalid signature timestamp")
+	ErrExpired          = errors.New("signature timestamp outside tolerance")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Sign calcula a assinatura de body para o timestamp informado, no formato do header X-Judger-Signature.
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest define os headers de timestamp e assinatura em req.
+func SignRequest(req *http.Request, secret []byte, body []byte) {
+	now := time.Now()
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(secret, now, body))
+}
+
+// Verify confere a assinatura de body a partir dos headers recebidos.
+func Verify(secret []byte, header http.Header, body []byte, tolerance time.Duration) error {
+	timestampStr := header.Get(TimestampHeader)
+	signature := header.Get(SignatureHeader)
+	if timestampStr == "" || signature == "" {
+		return ErrMissingHeaders
+	}
+
+	seconds, err := strconv.ParseInt(timestampStr, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	timestamp := time.Unix(seconds, 0)
+
+	age := time.Since(timestamp)
+	if age < 0 {
+		age = -age
+	}
+	if age > tolerance {
+		return ErrExpired
+	}
+
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+
+	expected := Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifyRequest lê o corpo de r e confere a assinatura, devolvendo o corpo para ser decodificado.
+func VerifyRequest(r *http.Request, secret []byte, tolerance time.Duration) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	if err := Verify(secret, r.Header, body, tolerance); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
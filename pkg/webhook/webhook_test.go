@@ -0,0 +1,95 @@
+package webhook_test
+
+import (
+	"IFJudger/pkg/webhook"
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	secret := []byte("segredo-compartilhado")
+	body := []byte(`{"event":"submission.completed","submission_id":"abc"}`)
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		timestamp time.Time
+		// o corpo assinado; o verificado é sempre body
+		signedBody []byte
+		// sobrescreve o header de timestamp enviado, para simular um reenvio com a data trocada
+		sentTimestamp string
+		want          error
+	}{
+		{name: "assinatura válida", secret: secret, timestamp: now, signedBody: body},
+		{name: "dentro da tolerância", secret: secret, timestamp: now.Add(-webhook.DefaultTolerance + time.Minute), signedBody: body},
+		{name: "corpo alterado", secret: secret, timestamp: now, signedBody: []byte(`{"event":"submission.failed","submission_id":"abc"}`), want: webhook.ErrInvalidSignature},
+		{name: "segredo errado", secret: []byte("outro-segredo"), timestamp: now, signedBody: body, want: webhook.ErrInvalidSignature},
+		{name: "timestamp antigo (replay)", secret: secret, timestamp: now.Add(-webhook.DefaultTolerance - time.Minute), signedBody: body, want: webhook.ErrExpired},
+		{name: "timestamp no futuro", secret: secret, timestamp: now.Add(webhook.DefaultTolerance + time.Minute), signedBody: body, want: webhook.ErrExpired},
+		{name: "timestamp trocado no reenvio", secret: secret, timestamp: now.Add(-time.Hour), signedBody: body, sentTimestamp: strconv.FormatInt(now.Unix(), 10), want: webhook.ErrInvalidSignature},
+		{name: "timestamp inválido", secret: secret, timestamp: now, signedBody: body, sentTimestamp: "ontem", want: webhook.ErrInvalidTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set(webhook.TimestampHeader, strconv.FormatInt(tt.timestamp.Unix(), 10))
+			if tt.sentTimestamp != "" {
+				header.Set(webhook.TimestampHeader, tt.sentTimestamp)
+			}
+			header.Set(webhook.SignatureHeader, webhook.Sign(tt.secret, tt.timestamp, tt.signedBody))
+
+			err := webhook.Verify(secret, header, body, webhook.DefaultTolerance)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyMissingHeaders(t *testing.T) {
+	secret := []byte("segredo-compartilhado")
+	body := []byte("{}")
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		header http.Header
+	}{
+		{name: "sem headers", header: http.Header{}},
+		{name: "sem assinatura", header: http.Header{webhook.TimestampHeader: {strconv.FormatInt(now.Unix(), 10)}}},
+		{name: "sem timestamp", header: http.Header{webhook.SignatureHeader: {webhook.Sign(secret, now, body)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := webhook.Verify(secret, tt.header, body, webhook.DefaultTolerance); !errors.Is(err, webhook.ErrMissingHeaders) {
+				t.Errorf("Verify = %v, esperado %v", err, webhook.ErrMissingHeaders)
+			}
+		})
+	}
+}
+
+// TestSignRequestRoundTrip confere que uma requisição assinada pelo judger passa em VerifyRequest
+// do lado do receptor, que devolve o corpo intacto.
+func TestSignRequestRoundTrip(t *testing.T) {
+	secret := []byte("segredo-compartilhado")
+	body := []byte(`{"event":"submission.processing"}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(body))
+	webhook.SignRequest(req, secret, body)
+
+	got, err := webhook.VerifyRequest(req, secret, webhook.DefaultTolerance)
+	if err != nil {
+		t.Fatalf("VerifyRequest: %v", err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("corpo = %q, esperado %q", got, body)
+	}
+}
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"sort"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+)
+
+// DockerProbe verifica se o daemon do Docker responde e se as imagens das linguagens estão no host.
+type DockerProbe struct {
+	client *client.Client
+}
+
+func NewDockerProbe() (*DockerProbe, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, err
+	}
+
+	return &DockerProbe{client: cli}, nil
+}
+
+func (p *DockerProbe) Ping(ctx context.Context) error {
+	_, err := p.client.Ping(ctx)
+	return err
+}
+
+// MissingImages retorna as imagens informadas que não estão presentes no host.
+func (p *DockerProbe) MissingImages(ctx context.Context, images []string) ([]string, error) {
+	var missing []string
+	for _, image := range images {
+		if _, err := p.client.ImageInspect(ctx, image); err != nil {
+			if errdefs.IsNotFound(err) {
+				missing = append(missing, image)
+				continue
+			}
+			return nil, err
+		}
+	}
+	sort.Strings(missing)
+
+	return missing, nil
+}
+
+func (p *DockerProbe) Close() error {
+	return p.client.Close()
+}
@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// Labels aplicados a todo container criado pelo judger, usados para encontrar containers órfãos.
+const (
+	LabelManaged    = "aquilles.judger"
+	LabelJobID      = "aquilles.judger.job-id"
+	LabelInstanceID = "aquilles.judger.instance-id"
+)
+
+type ManagedContainer struct {
+	ID         string
+	JobID      string
+	InstanceID string
+	State      string
+}
+
+// ContainerReaper lista e remove containers criados pelo judger.
+type ContainerReaper struct {
+	client *client.Client
+}
+
+func NewContainerReaper() (*ContainerReaper, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return nil, err
+	}
+	cli.NegotiateAPIVersion(context.Background())
+
+	return &ContainerReaper{client: cli}, nil
+}
+
+func (r *ContainerReaper) ListManaged(ctx context.Context) ([]ManagedContainer, error) {
+	summaries, err := r.client.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", LabelManaged)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]ManagedContainer, 0, len(summaries))
+	for _, summary := range summaries {
+		containers = append(containers, ManagedContainer{
+			ID:         summary.ID,
+			JobID:      summary.Labels[LabelJobID],
+			InstanceID: summary.Labels[LabelInstanceID],
+			State:      string(summary.State),
+		})
+	}
+
+	return containers, nil
+}
+
+func (r *ContainerReaper) Remove(ctx context.Context, containerID string) error {
+	return r.client.ContainerRemove(ctx, containerID, container.RemoveOptions{Force: true})
+}
+
+func (r *ContainerReaper) Close() error {
+	return r.client.Close()
+}
@@ -2,10 +2,17 @@ package worker
 
 import (
 	folderutils "IFJudger/pkg/folder_utils"
+	"IFJudger/pkg/logger"
+	"IFJudger/pkg/metrics"
+	"IFJudger/pkg/tracing"
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
@@ -13,16 +20,23 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
-)
-
-type LanguageID int
-
-const (
-	Python LanguageID = 1
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ExecutionReport struct {
 	Results []TestCaseResult `json:"results"`
+	Run     *RunResult       `json:"run,omitempty"`
+}
+
+type RunResult struct {
+	Status    string `json:"status"`
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	Truncated bool   `json:"truncated,omitempty"`
+	ExitCode  int    `json:"exit_code"`
+	TimeMS    int64  `json:"time_ms"`
+	MemoryKB  int64  `json:"memory_kb"`
 }
 
 type TestCaseResult struct {
@@ -32,23 +46,15 @@ type TestCaseResult struct {
 	Message string `json:"message,omitempty"`
 }
 
-var LanguageImages = map[LanguageID]string{
-	Python: "python:3.12.12-slim",
-}
-
-func (l LanguageID) String() string {
-	switch l {
-	case Python:
-		return "Python"
-	default:
-		return "Unknown"
-	}
-}
-
 type WorkerConfigData struct {
 	ContainerTimeout time.Duration
 	TestTimeout      time.Duration
 	MaximumRamMB     int
+	// identificam o container para o ContainerReaper
+	JobID      string
+	InstanceID string
+	// logger com os atributos do job; nil usa o slog.Default()
+	Logger *slog.Logger
 }
 
 type Worker struct {
@@ -57,24 +63,37 @@ type Worker struct {
 	hostConfig   *container.HostConfig
 
 	dataPath         string
-	language         LanguageID
 	maxRamMB         int
 	testTimeout      time.Duration
 	containerTimeout time.Duration
+	jobID            string
+	instanceID       string
+	logger           *slog.Logger
+	onProgress       func(TestCaseResult)
+	runMode          bool
+	outputLimit      int
 }
 
 func NewWorker(config WorkerConfigData) (*Worker, error) {
 	cli, err := client.NewClientWithOpts(client.FromEnv)
 	if err != nil {
-		return nil, err
+		return nil, countDockerError("client", err)
 	}
 	cli.NegotiateAPIVersion(context.Background())
 
+	workerLogger := config.Logger
+	if workerLogger == nil {
+		workerLogger = slog.Default()
+	}
+
 	return &Worker{
 		client:           cli,
 		containerTimeout: config.ContainerTimeout,
 		testTimeout:      config.TestTimeout,
 		maxRamMB:         config.MaximumRamMB,
+		jobID:            config.JobID,
+		instanceID:       config.InstanceID,
+		logger:           workerLogger,
 	}, nil
 }
 
@@ -84,7 +103,14 @@ type DockerWorkspaceConfig struct {
 	RunnerPath         string
 }
 
-func (w *Worker) PrepareWorkspace(config DockerWorkspaceConfig) error {
+func (w *Worker) PrepareWorkspace(ctx context.Context, config DockerWorkspaceConfig) (err error) {
+	start := time.Now()
+	_, span := tracing.Start(ctx, "worker.prepare_workspace")
+	defer func() {
+		metrics.JobStageDuration.WithLabelValues(metrics.StageWorkspacePrep).Observe(time.Since(start).Seconds())
+		tracing.End(span, err)
+	}()
+
 	if err := os.MkdirAll(config.ExecutionDirectory, 0755); err != nil {
 		return err
 	}
@@ -101,10 +127,20 @@ func (w *Worker) PrepareWorkspace(config DockerWorkspaceConfig) error {
 	}
 	w.dataPath = absPath
 
-	err = folderutils.CopyDir(config.CachePath, w.dataPath)
-	if err != nil {
-		w.Cleanup()
-		return err
+	// execuções no modo run não têm casos de teste para copiar
+	if config.CachePath != "" {
+		// o judger já grava a versão resolvida no job, mas jobs antigos ainda trazem o symlink do cache;
+		// resolvê-lo uma vez garante que a cópia inteira venha da mesma versão
+		cachePath, err := filepath.EvalSymlinks(config.CachePath)
+		if err != nil {
+			w.Cleanup()
+			return err
+		}
+		err = folderutils.CopyDir(cachePath, w.dataPath)
+		if err != nil {
+			w.Cleanup()
+			return err
+		}
 	}
 
 	if err := prepareRunnerBinary(config.RunnerPath, w.dataPath); err != nil {
@@ -130,19 +166,47 @@ func prepareRunnerBinary(sourcePath, destDir string) error {
 	return nil
 }
 
-func (w *Worker) SetupPython(sourceCode string) error {
-	w.language = Python
+// SetupRun coloca o worker no modo run: o programa é executado uma vez com input como entrada,
+// sem comparação de saída, e stdout/stderr são limitados a outputLimit bytes.
+// Deve ser chamado depois de PrepareWorkspace e antes do Setup da linguagem.
+func (w *Worker) SetupRun(input string, outputLimit int) error {
+	if err := os.WriteFile(filepath.Join(w.dataPath, "run.in"), []byte(input), 0644); err != nil {
+		return fmt.Errorf("erro ao criar arquivo de entrada: %w", err)
+	}
 
-	fullPath := filepath.Join(w.dataPath, "source.py")
+	w.runMode = true
+	w.outputLimit = outputLimit
+	return nil
+}
+
+func (w *Worker) runnerArgs() []string {
+	args := []string{fmt.Sprintf("--testTimeout=%d", w.testTimeout)}
+	if w.runMode {
+		args = append(args, "--mode=run", fmt.Sprintf("--outputLimit=%d", w.outputLimit))
+	}
+	return args
+}
+
+// LanguageConfig diz como executar o código de uma linguagem: a imagem do container, o arquivo
+// em que o código é gravado e o comando que o runner executa para cada caso de teste.
+type LanguageConfig struct {
+	Image      string
+	SourceFile string
+	RunCommand []string
+}
+
+func (w *Worker) SetupLanguage(language LanguageConfig, sourceCode string) error {
+	fullPath := filepath.Join(w.dataPath, language.SourceFile)
 
 	err := os.WriteFile(fullPath, []byte(sourceCode), 0644)
 	if err != nil {
 		return fmt.Errorf("erro ao criar arquivo do código fonte: %w", err)
 	}
 
+	cmd := append([]string{"./runner"}, language.RunCommand...)
 	w.clientConfig = &container.Config{
-		Image:      "python:3.12.12-slim",
-		Cmd:        []string{"./runner", "python", "source.py", fmt.Sprintf("--testTimeout=%d", w.testTimeout)},
+		Image:      language.Image,
+		Cmd:        append(cmd, w.runnerArgs()...),
 		WorkingDir: "/app",
 	}
 
@@ -164,50 +228,125 @@ func (w *Worker) SetupCustom(containerConfig *container.Config, hostConfig *cont
 	w.hostConfig = hostConfig
 }
 
+// OnProgress registra uma função chamada a cada caso de teste concluído pelo runner, durante o Execute.
+func (w *Worker) OnProgress(handler func(TestCaseResult)) {
+	w.onProgress = handler
+}
+
 func (w *Worker) Cleanup() {
 	if w.dataPath != "" {
 		os.RemoveAll(w.dataPath)
 	}
 }
 
-func (w *Worker) Execute() (ExecutionReport, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), w.containerTimeout)
+// Execute roda o container e lê o result.json gerado pelo runner.
+// Cancelar parentCtx interrompe a execução; o container é removido de qualquer forma.
+func (w *Worker) Execute(parentCtx context.Context) (ExecutionReport, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, w.containerTimeout)
 	defer cancel()
 
-	containerID, err := w.client.ContainerCreate(ctx, w.clientConfig, w.hostConfig, nil, nil, "")
+	if w.clientConfig.Labels == nil {
+		w.clientConfig.Labels = make(map[string]string)
+	}
+	w.clientConfig.Labels[LabelManaged] = "true"
+	w.clientConfig.Labels[LabelJobID] = w.jobID
+	w.clientConfig.Labels[LabelInstanceID] = w.instanceID
+
+	createCtx, span := tracing.Start(ctx, "container.create", trace.WithAttributes(attribute.String("container.image", w.clientConfig.Image)))
+	containerID, err := w.client.ContainerCreate(createCtx, w.clientConfig, w.hostConfig, nil, nil, "")
+	tracing.End(span, err)
 	if err != nil {
-		return ExecutionReport{}, err
+		return ExecutionReport{}, countDockerError("create", err)
 	}
-	defer w.client.ContainerRemove(context.Background(), containerID.ID, container.RemoveOptions{Force: true})
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String(logger.KeyContainerID, containerID.ID))
+	containerLog := w.logger.With(logger.KeyContainerID, containerID.ID)
+	containerLog.Debug("Container criado", "image", w.clientConfig.Image, "memory_mb", w.maxRamMB, "test_timeout", w.testTimeout.String())
+	defer func() {
+		err := w.client.ContainerRemove(context.Background(), containerID.ID, container.RemoveOptions{Force: true})
+		if countDockerError("remove", err) != nil {
+			containerLog.Warn("Falha ao remover container", logger.Err(err))
+		}
+	}()
 
-	err = w.client.ContainerStart(ctx, containerID.ID, container.StartOptions{})
+	startCtx, span := tracing.Start(ctx, "container.start")
+	err = w.client.ContainerStart(startCtx, containerID.ID, container.StartOptions{})
+	tracing.End(span, err)
 	if err != nil {
+		return ExecutionReport{}, countDockerError("start", err)
+	}
+
+	started := time.Now()
+	defer func() {
+		metrics.JobStageDuration.WithLabelValues(metrics.StageContainer).Observe(time.Since(started).Seconds())
+	}()
+	containerLog.Info("Container em execução")
+
+	var progressDone chan struct{}
+	if w.onProgress != nil {
+		progressDone = make(chan struct{})
+		go func() {
+			defer close(progressDone)
+			w.followProgress(ctx, containerID.ID)
+		}()
+	}
+
+	if err := w.wait(ctx, parentCtx, containerID.ID, containerLog, started); err != nil {
 		return ExecutionReport{}, err
 	}
 
-	statusCh, errCh := w.client.ContainerWait(ctx, containerID.ID, container.WaitConditionNotRunning)
+	// garante que os últimos eventos de progresso sejam entregues antes do resultado final
+	if progressDone != nil {
+		select {
+		case <-progressDone:
+		case <-time.After(progressDrainTimeout):
+		}
+	}
+
+	_, span = tracing.Start(ctx, "report.parse")
+	executionReport, err := w.readReport(containerID.ID)
+	tracing.End(span, err)
+	return executionReport, err
+}
+
+// wait espera o container terminar, distinguindo o cancelamento do job do timeout do container.
+func (w *Worker) wait(ctx, parentCtx context.Context, containerID string, containerLog *slog.Logger, started time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "container.wait")
+	defer func() { tracing.End(span, err) }()
+
+	statusCh, errCh := w.client.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
 
 	select {
 	case err := <-errCh:
 		if err != nil {
-			return ExecutionReport{}, err
+			return countDockerError("wait", err)
 		}
 
-	case <-statusCh:
-		break
+	case status := <-statusCh:
+		span.SetAttributes(attribute.Int64("container.exit_code", status.StatusCode))
+		containerLog.Info("Container finalizado", "exit_code", status.StatusCode, "duration_ms", time.Since(started).Milliseconds())
 
 	case <-ctx.Done():
-		return ExecutionReport{}, fmt.Errorf("Timeout do Container excedido.")
+		if parentCtx.Err() != nil {
+			return fmt.Errorf("execução cancelada: %w", parentCtx.Err())
+		}
+		containerLog.Warn("Timeout do container excedido", "timeout", w.containerTimeout.String())
+		return fmt.Errorf("Timeout do Container excedido.")
 	}
 
+	return nil
+}
+
+// readReport lê o result.json gerado pelo runner; se ele não existir, devolve os logs do container no erro.
+func (w *Worker) readReport(containerID string) (ExecutionReport, error) {
 	resultPath := filepath.Join(w.dataPath, "result.json")
 	content, err := os.ReadFile(resultPath)
 	if err != nil {
-		out, errLogs := w.client.ContainerLogs(context.Background(), containerID.ID, container.LogsOptions{
+		out, errLogs := w.client.ContainerLogs(context.Background(), containerID, container.LogsOptions{
 			ShowStdout: true,
 			ShowStderr: true,
 		})
 
+		countDockerError("logs", errLogs)
 		if errLogs == nil {
 			var stdoutBuf, stderrBuf bytes.Buffer
 			stdcopy.StdCopy(&stdoutBuf, &stderrBuf, out)
@@ -230,3 +369,48 @@ func (w *Worker) Execute() (ExecutionReport, error) {
 
 	return executionReport, nil
 }
+
+// countDockerError contabiliza erros do daemon em metrics.DockerErrors e devolve o próprio erro.
+// Cancelamentos e timeouts do contexto não contam: são do judger, não do Docker.
+func countDockerError(operation string, err error) error {
+	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		metrics.DockerErrors.WithLabelValues(operation).Inc()
+	}
+	return err
+}
+
+// tempo máximo esperando o stream de logs terminar depois que o container parou
+const progressDrainTimeout = 2 * time.Second
+
+type progressLine struct {
+	Type   string         `json:"type"`
+	Result TestCaseResult `json:"result"`
+}
+
+// followProgress lê o stdout do runner enquanto o container roda e repassa
+// cada linha de progresso para o handler registrado em OnProgress.
+func (w *Worker) followProgress(ctx context.Context, containerID string) {
+	logs, err := w.client.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		Follow:     true,
+	})
+	if err != nil {
+		return
+	}
+	defer logs.Close()
+
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(writer, io.Discard, logs)
+		writer.CloseWithError(err)
+	}()
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		var line progressLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil || line.Type != "test_case" {
+			continue
+		}
+		w.onProgress(line.Result)
+	}
+}